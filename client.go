@@ -0,0 +1,152 @@
+// Package xaio is the public entry point of x-aio.
+// It ties the GraphQL operations scraper, the transaction id generator and the request client together.
+package xaio
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+
+	requestClient "github.com/nitayStain/x-aio/internal/request-client"
+	"github.com/nitayStain/x-aio/operations"
+	"github.com/nitayStain/x-aio/tid"
+)
+
+var ErrOperationNotFound = errors.New("operation not found")
+
+type Client struct {
+	requests    *requestClient.RequestClient // underlying request client, shared by every call
+	transaction *tid.ClientTransaction       // used to sign requests with x-client-transaction-id
+
+	features     map[string]bool // feature switch values sent with every GraphQL request
+	fieldToggles map[string]bool // field toggle values sent with every GraphQL request
+
+	scrapeOptions operations.Options // used whenever the operations are scraped
+
+	mu         sync.RWMutex
+	operations map[string]operations.Operation // operations registry, keyed by operation name
+}
+
+// initiates a new client, scraping whatever was not supplied through the options
+func NewClient(ctx context.Context, opts ...Option) (*Client, error) {
+	cfg := defaultConfig()
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	c := &Client{
+		requests:     requestClient.NewClient(cfg.userAgent, cfg.headers, cfg.cookies),
+		features:     cfg.features,
+		fieldToggles: cfg.fieldToggles,
+	}
+
+	if cfg.httpClient != nil {
+		c.requests.Client = cfg.httpClient
+	}
+
+	c.scrapeOptions = operations.Options{
+		Client:    c.requests.Client,
+		UserAgent: cfg.userAgent,
+	}
+
+	fetcher := tid.NewHTTPFetcher(c.requests.Client)
+	fetcher.UserAgent = cfg.userAgent
+
+	// the transaction and the operations are both derived from the home page, it is fetched once for both
+	var homePage string
+	if cfg.transaction == nil || cfg.operations == nil {
+		page, err := fetcher.FetchHomePage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		homePage = page
+	}
+
+	c.transaction = cfg.transaction
+	if c.transaction == nil {
+		transaction, err := tid.NewClientTransactionFromHomePage(ctx, homePage, fetcher)
+		if err != nil {
+			return nil, err
+		}
+		c.transaction = transaction
+	}
+
+	ops := cfg.operations
+	bearerToken := cfg.bearerToken
+	if ops == nil {
+		snapshot, err := operations.GetSnapshotFromHomePage(ctx, c.scrapeOptions, homePage)
+		if err != nil {
+			return nil, err
+		}
+		ops = snapshot.Operations
+
+		if bearerToken == "" {
+			bearerToken = snapshot.BearerToken
+		}
+	}
+	c.setOperations(ops)
+
+	// an explicit Authorization header always wins over the scraped token
+	if bearerToken != "" && c.requests.Headers.Get("Authorization") == "" {
+		c.requests.Headers.Set("Authorization", "Bearer "+bearerToken)
+	}
+
+	return c, nil
+}
+
+// returns the http client every request is sent with
+func (c *Client) HTTPClient() *http.Client {
+	return c.requests.Client
+}
+
+// returns the transaction used to generate x-client-transaction-id headers
+func (c *Client) Transaction() *tid.ClientTransaction {
+	return c.transaction
+}
+
+// looks up an operation in the registry by its name
+func (c *Client) Operation(name string) (operations.Operation, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	op, ok := c.operations[name]
+	if !ok {
+		return operations.Operation{}, ErrOperationNotFound
+	}
+	return op, nil
+}
+
+// returns a copy of every registered operation
+func (c *Client) Operations() []operations.Operation {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	ops := make([]operations.Operation, 0, len(c.operations))
+	for _, op := range c.operations {
+		ops = append(ops, op)
+	}
+	return ops
+}
+
+// scrapes the operations again and replaces the registry
+func (c *Client) RefreshOperations(ctx context.Context) error {
+	ops, err := operations.GetOperationsWithOptions(ctx, c.scrapeOptions)
+	if err != nil {
+		return err
+	}
+
+	c.setOperations(ops)
+	return nil
+}
+
+func (c *Client) setOperations(ops []operations.Operation) {
+	registry := make(map[string]operations.Operation, len(ops))
+	for _, op := range ops {
+		registry[op.OperationName] = op
+	}
+
+	c.mu.Lock()
+	c.operations = registry
+	c.mu.Unlock()
+}
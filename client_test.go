@@ -0,0 +1,65 @@
+package xaio
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestNewClientFetchesHomePageOnce(t *testing.T) {
+	const scriptsURL = "https://abs.twimg.com/responsive-web/client-web/"
+	key := "Zqgg6jtxHIuDXxl6QDgmcWwgMfgAJzRXLhUQrDqWMR0o63Ty+9xlD/5mZQtEPJzP"
+
+	var frames strings.Builder
+	for i := 0; i < 4; i++ {
+		fmt.Fprintf(&frames, `<svg id="loading-x-anim-%d"><g><path d="M 0 0"></path><path d="M 10,30 C 200 100 50 80 140 30 120 40 10 210 30"></path></g></svg>`, i)
+	}
+	pages := map[string]string{
+		"https://x.com": `<html><head><meta name="twitter-site-verification" content="` + key + `">` +
+			`<link rel="preload" as="script" href="` + scriptsURL + `main.abc123a.js"></head><body>` + frames.String() +
+			`<script>o.u=e=>e+"."+{"ondemand.s":"def456"}[e]+"a.js"</script></body></html>`,
+		scriptsURL + "ondemand.s.def456a.js": "parseInt(n[2], 16);parseInt(n[12], 16);",
+		scriptsURL + "main.abc123a.js":       `e.exports={queryId:"abc",operationName:"Test",operationType:"query",metadata:{featureSwitches:[],fieldToggles:[]}}`,
+	}
+
+	var mu sync.Mutex
+	fetches := map[string]int{}
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		fetches[req.URL.String()]++
+		mu.Unlock()
+
+		page, ok := pages[req.URL.String()]
+		status := http.StatusOK
+		if !ok {
+			status = http.StatusNotFound
+		}
+		return &http.Response{
+			StatusCode: status,
+			Header:     http.Header{},
+			Body:       io.NopCloser(strings.NewReader(page)),
+			Request:    req,
+		}, nil
+	})
+
+	c, err := NewClient(context.Background(), WithHTTPClient(&http.Client{Transport: transport}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if c.Transaction().VerificationKey != key {
+		t.Fatalf("got verification key %q", c.Transaction().VerificationKey)
+	}
+	if _, err := c.Operation("Test"); err != nil {
+		t.Fatal(err)
+	}
+	for url := range pages {
+		if fetches[url] != 1 {
+			t.Errorf("%s fetched %d times, want once", url, fetches[url])
+		}
+	}
+}
@@ -0,0 +1,165 @@
+// Command opgen generates typed Go constants and call stubs from an operations snapshot.
+//
+//	opgen -in operations.json -out operations_gen.go [-pkg xaio]
+//
+// It is meant to be run from a go:generate directive, e.g.
+//
+//	//go:generate go run github.com/nitayStain/x-aio/cmd/opgen -in operations.json -pkg mypkg -out operations_gen.go
+//
+// When the package is xaio itself the stubs are methods of xaio.Client, otherwise they are
+// methods of a generated Client type that embeds it.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/nitayStain/x-aio/operations"
+)
+
+const xaioImport = "github.com/nitayStain/x-aio"
+
+// methods of xaio.Client that a generated stub must not shadow
+var reservedMethods = map[string]bool{
+	"Execute":           true,
+	"HTTPClient":        true,
+	"Operation":         true,
+	"Operations":        true,
+	"RefreshOperations": true,
+	"Transaction":       true,
+}
+
+func main() {
+	in := flag.String("in", "", "operations snapshot to generate from")
+	out := flag.String("out", "operations_gen.go", "output file")
+	pkg := flag.String("pkg", "xaio", "package of the generated file")
+	flag.Parse()
+
+	if *in == "" {
+		fmt.Fprintln(os.Stderr, "usage: opgen -in operations.json [-out file] [-pkg name]")
+		os.Exit(2)
+	}
+
+	ops, err := operations.LoadOperationsFile(*in)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	src, err := generate(ops, *pkg, filepath.Base(*in))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	if err := os.WriteFile(*out, src, 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+}
+
+// generate renders the Go source for the given operations, sorted by name so regenerated files diff cleanly
+func generate(ops []operations.Operation, pkg, source string) ([]byte, error) {
+	byName := map[string]operations.Operation{}
+	for _, op := range ops {
+		if _, ok := byName[op.OperationName]; !ok {
+			byName[op.OperationName] = op
+		}
+	}
+
+	names := make([]string, 0, len(byName))
+	for name := range byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	external := pkg != "xaio"
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by opgen from %s; DO NOT EDIT.\n\n", source)
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+
+	if len(names) > 0 {
+		b.WriteString("import (\n\t\"context\"\n")
+		if external {
+			fmt.Fprintf(&b, "\n\txaio %q\n", xaioImport)
+		}
+		b.WriteString(")\n\n")
+	}
+
+	if external {
+		b.WriteString("// Client adds a typed method per operation to xaio.Client\n")
+		b.WriteString("type Client struct {\n\t*xaio.Client\n}\n\n")
+	}
+
+	seen := map[string]string{}
+	for _, name := range names {
+		op := byName[name]
+
+		ident := goIdent(name)
+		if other, ok := seen[ident]; ok {
+			return nil, fmt.Errorf("operations %s and %s map to the same identifier %s", other, name, ident)
+		}
+		seen[ident] = name
+
+		fmt.Fprintf(&b, "// %s operation\n", name)
+		b.WriteString("const (\n")
+		fmt.Fprintf(&b, "\tOp%s = %q\n", ident, op.OperationName)
+		fmt.Fprintf(&b, "\tOp%sQueryID = %q\n", ident, op.QueryID)
+		fmt.Fprintf(&b, "\tOp%sType = %q\n", ident, op.OperationType)
+		b.WriteString(")\n\n")
+
+		b.WriteString("var (\n")
+		fmt.Fprintf(&b, "\tOp%sFeatureSwitches = %s\n", ident, stringSlice(op.FeatureSwitches))
+		fmt.Fprintf(&b, "\tOp%sFieldToggles = %s\n", ident, stringSlice(op.FieldToggles))
+		b.WriteString(")\n\n")
+
+		// outside xaio, Client is also the embedded field of the generated Client type
+		method := ident
+		if reservedMethods[method] || (external && method == "Client") {
+			method += "Op"
+		}
+		fmt.Fprintf(&b, "// %s executes the %s %s\n", method, op.OperationName, op.OperationType)
+		fmt.Fprintf(&b, "func (c *Client) %s(ctx context.Context, variables map[string]any) (map[string]any, error) {\n", method)
+		fmt.Fprintf(&b, "\treturn c.Execute(ctx, Op%s, variables)\n", ident)
+		b.WriteString("}\n\n")
+	}
+
+	return format.Source(b.Bytes())
+}
+
+// turns an operation name into an exported Go identifier
+func goIdent(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			b.WriteRune(r)
+		}
+	}
+
+	ident := []rune(b.String())
+	if len(ident) == 0 || !unicode.IsLetter(ident[0]) {
+		ident = append([]rune("X"), ident...)
+	}
+	ident[0] = unicode.ToUpper(ident[0])
+	return string(ident)
+}
+
+func stringSlice(items []string) string {
+	if len(items) == 0 {
+		return "[]string{}"
+	}
+
+	quoted := make([]string, len(items))
+	for i, item := range items {
+		quoted[i] = fmt.Sprintf("%q", item)
+	}
+	return "[]string{\n" + strings.Join(quoted, ",\n") + ",\n}"
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/nitayStain/x-aio/operations"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files")
+
+func TestGenerateGolden(t *testing.T) {
+	ops, err := operations.LoadOperationsFile("testdata/operations.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		pkg    string
+		golden string
+	}{
+		{"xaio", "testdata/xaio.golden"},
+		{"external", "testdata/external/operations_gen.go"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.pkg, func(t *testing.T) {
+			got, err := generate(ops, test.pkg, "operations.json")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if *update {
+				if err := os.WriteFile(test.golden, got, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			want, err := os.ReadFile(test.golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Fatalf("generated code differs from %s, rerun with -update to accept it:\n%s", test.golden, got)
+			}
+		})
+	}
+}
+
+// the code generated for another package wraps xaio.Client, it must compile against it
+func TestGenerateExternalCompiles(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds the generated package")
+	}
+
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go tool not found")
+	}
+
+	cmd := exec.Command(goTool, "build", "./"+filepath.Join("testdata", "external"))
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("generated package does not build: %v\n%s", err, out)
+	}
+}
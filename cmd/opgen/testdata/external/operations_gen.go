@@ -0,0 +1,87 @@
+// Code generated by opgen from operations.json; DO NOT EDIT.
+
+package external
+
+import (
+	"context"
+
+	xaio "github.com/nitayStain/x-aio"
+)
+
+// Client adds a typed method per operation to xaio.Client
+type Client struct {
+	*xaio.Client
+}
+
+// Client operation
+const (
+	OpClient        = "Client"
+	OpClientQueryID = "aaaaaaaaaaaaaaaaaaaaaa"
+	OpClientType    = "query"
+)
+
+var (
+	OpClientFeatureSwitches = []string{}
+	OpClientFieldToggles    = []string{}
+)
+
+// ClientOp executes the Client query
+func (c *Client) ClientOp(ctx context.Context, variables map[string]any) (map[string]any, error) {
+	return c.Execute(ctx, OpClient, variables)
+}
+
+// CreateTweet operation
+const (
+	OpCreateTweet        = "CreateTweet"
+	OpCreateTweetQueryID = "SoVnbfCycZ7fERGCwpZkYA"
+	OpCreateTweetType    = "mutation"
+)
+
+var (
+	OpCreateTweetFeatureSwitches = []string{}
+	OpCreateTweetFieldToggles    = []string{}
+)
+
+// CreateTweet executes the CreateTweet mutation
+func (c *Client) CreateTweet(ctx context.Context, variables map[string]any) (map[string]any, error) {
+	return c.Execute(ctx, OpCreateTweet, variables)
+}
+
+// Execute operation
+const (
+	OpExecute        = "Execute"
+	OpExecuteQueryID = "bbbbbbbbbbbbbbbbbbbbbb"
+	OpExecuteType    = "mutation"
+)
+
+var (
+	OpExecuteFeatureSwitches = []string{}
+	OpExecuteFieldToggles    = []string{}
+)
+
+// ExecuteOp executes the Execute mutation
+func (c *Client) ExecuteOp(ctx context.Context, variables map[string]any) (map[string]any, error) {
+	return c.Execute(ctx, OpExecute, variables)
+}
+
+// UserByScreenName operation
+const (
+	OpUserByScreenName        = "UserByScreenName"
+	OpUserByScreenNameQueryID = "1VOOyvKkiI3FMmkeDNxM9A"
+	OpUserByScreenNameType    = "query"
+)
+
+var (
+	OpUserByScreenNameFeatureSwitches = []string{
+		"hidden_profile_subscriptions_enabled",
+		"verified_phone_label_enabled",
+	}
+	OpUserByScreenNameFieldToggles = []string{
+		"withAuxiliaryUserLabels",
+	}
+)
+
+// UserByScreenName executes the UserByScreenName query
+func (c *Client) UserByScreenName(ctx context.Context, variables map[string]any) (map[string]any, error) {
+	return c.Execute(ctx, OpUserByScreenName, variables)
+}
@@ -0,0 +1,117 @@
+// Command x-aio scrapes X's GraphQL operations and compares snapshots of them.
+//
+//	x-aio snapshot [-all] [-o file]    writes the current operations as JSON
+//	x-aio diff [-json] old.json new.json
+//
+// diff exits with status 1 when the snapshots differ, so it can drive alerts from a cron job.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/nitayStain/x-aio/operations"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "snapshot":
+		err = runSnapshot(os.Args[2:])
+	case "diff":
+		err = runDiff(os.Args[2:])
+	default:
+		usage()
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(2)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: x-aio snapshot [-all] [-o file]")
+	fmt.Fprintln(os.Stderr, "       x-aio diff [-json] old.json new.json")
+	os.Exit(2)
+}
+
+func runSnapshot(args []string) error {
+	fs := flag.NewFlagSet("snapshot", flag.ExitOnError)
+	all := fs.Bool("all", false, "scrape every webpack chunk, not only main.js")
+	out := fs.String("o", "", "output file, stdout when empty")
+	fs.Parse(args)
+
+	var ops []operations.Operation
+	var err error
+	if *all {
+		ops, err = operations.GetAllOperations(context.Background(), operations.ChunkOptions{})
+		// chunks that failed are reported, the snapshot still holds what was scraped
+		if err != nil && ops != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			err = nil
+		}
+	} else {
+		ops, err = operations.GetOperations()
+	}
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(ops)
+}
+
+func runDiff(args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "print the changes as JSON")
+	fs.Parse(args)
+
+	if fs.NArg() != 2 {
+		usage()
+	}
+
+	before, err := operations.LoadOperationsFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	after, err := operations.LoadOperationsFile(fs.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	changes := operations.Diff(before, after)
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(changes); err != nil {
+			return err
+		}
+	} else {
+		fmt.Print(changes.String())
+	}
+
+	if !changes.Empty() {
+		os.Exit(1)
+	}
+	return nil
+}
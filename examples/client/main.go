@@ -0,0 +1,24 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	xaio "github.com/nitayStain/x-aio"
+)
+
+func main() {
+	client, err := xaio.NewClient(context.Background())
+	if err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+
+	op, err := client.Operation("UserByScreenName")
+	if err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+
+	fmt.Printf("Name: %s | Type: %s | QueryID: %s\n", op.OperationName, op.OperationType, op.QueryID)
+}
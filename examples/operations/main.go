@@ -3,7 +3,7 @@ package main
 import (
 	"fmt"
 
-	"github.com/nitayStain/x-aio/internal/operations"
+	"github.com/nitayStain/x-aio/operations"
 )
 
 func main() {
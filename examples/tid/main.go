@@ -1,13 +1,46 @@
 package main
 
 import (
+	"encoding/json"
+	"flag"
+	"fmt"
 	"net/http"
+	"os"
+
+	"github.com/nitayStain/x-aio/tid"
 )
 
 func main() {
+	dumpTrace := flag.Bool("trace", false, "print the trace of deriving the animation key as JSON")
+	flag.Parse()
+
 	client := &http.Client{
 		Transport: &http.Transport{
 			ForceAttemptHTTP2: true,
 		},
 	}
+
+	trace := &tid.Trace{}
+	transaction, err := tid.NewClientTransaction(client, tid.WithTrace(trace))
+	if err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+
+	if *dumpTrace {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(trace); err != nil {
+			fmt.Println(err.Error())
+			return
+		}
+	}
+
+	id, err := transaction.GenerateTransactionID("GET", "/i/api/1.1/jot/client_event.json")
+	if err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+
+	fmt.Println(id)
 }
@@ -0,0 +1,130 @@
+package xaio
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/nitayStain/x-aio/operations"
+)
+
+const (
+	graphqlBaseURL = "https://x.com"
+	graphqlPath    = "/i/api/graphql"
+
+	// queries with a longer url than this are sent as POST instead
+	maxQueryURLLength = 4096
+)
+
+// APIError is returned when X answers a GraphQL request with a non-2xx status
+type APIError struct {
+	Operation string
+	Status    int
+	Body      string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s: graphql request failed with status %d", e.Operation, e.Status)
+}
+
+// Execute runs a registered GraphQL operation by its name and returns the decoded JSON response.
+// Queries are sent as GET, mutations (and queries whose url would be too long) as POST.
+func (c *Client) Execute(ctx context.Context, name string, variables map[string]any) (map[string]any, error) {
+	op, err := c.Operation(name)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	if variables == nil {
+		variables = map[string]any{}
+	}
+
+	path := fmt.Sprintf("%s/%s/%s", graphqlPath, op.QueryID, op.OperationName)
+	features := operations.ResolveFeatures(op.FeatureSwitches, op.Features, c.features)
+	fieldToggles := operations.ResolveFeatures(op.FieldToggles, nil, c.fieldToggles)
+
+	query, err := encodeQuery(variables, features, fieldToggles)
+	if err != nil {
+		return nil, err
+	}
+
+	method := http.MethodGet
+	requestURL := graphqlBaseURL + path + "?" + query.Encode()
+	if op.OperationType == "mutation" || len(requestURL) > maxQueryURLLength {
+		method = http.MethodPost
+		requestURL = graphqlBaseURL + path
+	}
+
+	transactionID, err := c.transaction.GenerateTransactionID(method, path)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := map[string]string{
+		"x-client-transaction-id": transactionID,
+	}
+	if csrf, ok := c.requests.Cookies["ct0"]; ok {
+		headers["x-csrf-token"] = csrf
+	}
+
+	var body io.Reader
+	if method == http.MethodPost {
+		payload, err := json.Marshal(map[string]any{
+			"queryId":      op.QueryID,
+			"variables":    variables,
+			"features":     features,
+			"fieldToggles": fieldToggles,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		body = bytes.NewReader(payload)
+		headers["Content-Type"] = "application/json"
+	}
+
+	res, err := c.requests.MakeRequestWithContext(ctx, method, requestURL, body, headers)
+	if err != nil {
+		return nil, err
+	}
+	c.transaction.Skew.Observe(res.Date(), res.ReceivedAt())
+
+	if res.Status() < 200 || res.Status() > 299 {
+		return nil, &APIError{Operation: op.OperationName, Status: res.Status(), Body: res.Payload()}
+	}
+
+	var result map[string]any
+	if err := json.Unmarshal([]byte(res.Payload()), &result); err != nil {
+		return nil, fmt.Errorf("%s: decoding response: %w", op.OperationName, err)
+	}
+
+	return result, nil
+}
+
+// builds the query string of a GET GraphQL request, every parameter is a JSON document
+func encodeQuery(variables map[string]any, features, fieldToggles map[string]bool) (url.Values, error) {
+	query := url.Values{}
+
+	params := []struct {
+		name  string
+		value any
+	}{
+		{"variables", variables},
+		{"features", features},
+		{"fieldToggles", fieldToggles},
+	}
+
+	for _, p := range params {
+		encoded, err := json.Marshal(p.value)
+		if err != nil {
+			return nil, fmt.Errorf("encoding %s: %w", p.name, err)
+		}
+		query.Set(p.name, string(encoded))
+	}
+
+	return query, nil
+}
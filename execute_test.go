@@ -0,0 +1,67 @@
+package xaio
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nitayStain/x-aio/operations"
+	"github.com/nitayStain/x-aio/tid"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestExecuteObservesClockSkew(t *testing.T) {
+	serverTime := time.Now().Add(time.Hour)
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if req.Header.Get("x-client-transaction-id") == "" {
+			t.Error("request sent without a transaction id")
+		}
+
+		header := http.Header{}
+		header.Set("Date", serverTime.UTC().Format(http.TimeFormat))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     header,
+			Body:       io.NopCloser(strings.NewReader(`{"data":{}}`)),
+			Request:    req,
+		}, nil
+	})
+
+	skew := tid.NewClockSkew()
+	transaction := &tid.ClientTransaction{
+		AdditionalRandomNumber: 3,
+		DefaultKeyword:         "obfiowerehiring",
+		KeyBytes:               make([]byte, 48),
+		AnimationKey:           "0",
+		Skew:                   skew,
+	}
+
+	c, err := NewClient(context.Background(),
+		WithHTTPClient(&http.Client{Transport: transport}),
+		WithTransaction(transaction),
+		WithOperations([]operations.Operation{{QueryID: "abc", OperationName: "Test", OperationType: "query"}}),
+		WithBearerToken("token"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.Execute(context.Background(), "Test", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if skew.Samples() != 1 {
+		t.Fatalf("got %d skew samples, want 1", skew.Samples())
+	}
+	if offset := skew.Offset(); offset < 59*time.Minute || offset > 61*time.Minute {
+		t.Fatalf("got offset %v, want about an hour", offset)
+	}
+}
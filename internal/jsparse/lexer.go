@@ -0,0 +1,406 @@
+package jsparse
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+type TokenKind int
+
+const (
+	EOFToken TokenKind = iota
+	PunctToken
+	IdentToken
+	StringToken
+	TemplateToken
+	RegexToken
+	NumberToken
+)
+
+func (k TokenKind) String() string {
+	switch k {
+	case EOFToken:
+		return "EOF"
+	case PunctToken:
+		return "punctuator"
+	case IdentToken:
+		return "identifier"
+	case StringToken:
+		return "string"
+	case TemplateToken:
+		return "template"
+	case RegexToken:
+		return "regex"
+	case NumberToken:
+		return "number"
+	}
+	return "unknown"
+}
+
+type Token struct {
+	Kind  TokenKind
+	Text  string // raw source of the token
+	Value string // decoded value of string literals
+	Start int    // offset of the token's first byte
+	End   int    // offset right after the token's last byte
+}
+
+// punctuators longer than a single byte, longest first so the lexer can match greedily
+var multiPunct = []string{
+	">>>=", "...", "===", "!==", "**=", "<<=", ">>=", ">>>", "&&=", "||=", "??=",
+	"=>", "==", "!=", "<=", ">=", "&&", "||", "??", "?.", "++", "--",
+	"+=", "-=", "*=", "/=", "%=", "&=", "|=", "^=", "**", "<<", ">>",
+}
+
+// keywords after which a slash starts a regex rather than a division
+var regexKeywords = map[string]bool{
+	"return": true, "typeof": true, "instanceof": true, "in": true, "of": true, "new": true,
+	"delete": true, "void": true, "throw": true, "case": true, "do": true, "else": true,
+	"yield": true, "await": true,
+}
+
+/*
+Lexer splits JavaScript source into tokens. It is only meant to walk minified bundles,
+so it knows just enough of the grammar to tell strings, templates, regexes and comments
+apart from the code around them.
+*/
+type Lexer struct {
+	src  []byte
+	pos  int
+	prev *Token // last significant token, used to tell regexes from divisions
+}
+
+// initiates a new lexer over the given source
+func NewLexer(src []byte) *Lexer {
+	return &Lexer{src: src}
+}
+
+// returns the offset the lexer is at
+func (l *Lexer) Pos() int {
+	return l.pos
+}
+
+// Next returns the next token, comments and whitespace are skipped.
+func (l *Lexer) Next() (Token, error) {
+	if err := l.skipTrivia(); err != nil {
+		return Token{}, err
+	}
+
+	if l.pos >= len(l.src) {
+		return Token{Kind: EOFToken, Start: l.pos, End: l.pos}, nil
+	}
+
+	start := l.pos
+	c := l.src[l.pos]
+
+	var tok Token
+	var err error
+	switch {
+	case c == '"' || c == '\'':
+		tok, err = l.lexString(c)
+	case c == '`':
+		tok, err = l.lexTemplate()
+	case c == '/' && l.regexAllowed():
+		tok, err = l.lexRegex()
+	case isDigit(c) || (c == '.' && l.pos+1 < len(l.src) && isDigit(l.src[l.pos+1])):
+		tok = l.lexNumber()
+	case isIdentStart(c):
+		tok = l.lexIdent()
+	default:
+		tok = l.lexPunct()
+	}
+	if err != nil {
+		return Token{}, err
+	}
+
+	tok.Start = start
+	tok.End = l.pos
+	tok.Text = string(l.src[start:l.pos])
+	l.prev = &tok
+	return tok, nil
+}
+
+func (l *Lexer) skipTrivia() error {
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == '\v':
+			l.pos++
+		case c == '/' && l.peek(1) == '/':
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' {
+				l.pos++
+			}
+		case c == '/' && l.peek(1) == '*':
+			end := strings.Index(string(l.src[l.pos+2:]), "*/")
+			if end < 0 {
+				return l.errorf("unterminated comment")
+			}
+			l.pos += end + 4
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// a slash starts a regex unless it follows something that ends an expression
+func (l *Lexer) regexAllowed() bool {
+	if l.prev == nil {
+		return true
+	}
+
+	switch l.prev.Kind {
+	case NumberToken, StringToken, TemplateToken, RegexToken:
+		return false
+	case IdentToken:
+		return regexKeywords[l.prev.Text]
+	case PunctToken:
+		switch l.prev.Text {
+		case ")", "]", "}", "++", "--":
+			return false
+		}
+	}
+	return true
+}
+
+func (l *Lexer) lexString(quote byte) (Token, error) {
+	l.pos++
+
+	var value strings.Builder
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch {
+		case c == quote:
+			l.pos++
+			return Token{Kind: StringToken, Value: value.String()}, nil
+		case c == '\\':
+			if err := l.lexEscape(&value); err != nil {
+				return Token{}, err
+			}
+		case c == '\n':
+			return Token{}, l.errorf("unterminated string")
+		default:
+			value.WriteByte(c)
+			l.pos++
+		}
+	}
+	return Token{}, l.errorf("unterminated string")
+}
+
+// decodes a single escape sequence inside a string literal
+func (l *Lexer) lexEscape(value *strings.Builder) error {
+	l.pos++
+	if l.pos >= len(l.src) {
+		return l.errorf("unterminated escape sequence")
+	}
+
+	c := l.src[l.pos]
+	l.pos++
+	switch c {
+	case 'n':
+		value.WriteByte('\n')
+	case 't':
+		value.WriteByte('\t')
+	case 'r':
+		value.WriteByte('\r')
+	case 'b':
+		value.WriteByte('\b')
+	case 'f':
+		value.WriteByte('\f')
+	case 'v':
+		value.WriteByte('\v')
+	case '0':
+		value.WriteByte(0)
+	case '\n':
+		// line continuation
+	case 'x':
+		return l.lexHexEscape(value, 2)
+	case 'u':
+		if l.peek(0) == '{' {
+			end := strings.IndexByte(string(l.src[l.pos:]), '}')
+			if end < 0 {
+				return l.errorf("invalid unicode escape")
+			}
+			r, err := strconv.ParseUint(string(l.src[l.pos+1:l.pos+end]), 16, 32)
+			if err != nil {
+				return l.errorf("invalid unicode escape")
+			}
+			value.WriteRune(rune(r))
+			l.pos += end + 1
+			return nil
+		}
+		return l.lexHexEscape(value, 4)
+	default:
+		value.WriteByte(c)
+	}
+	return nil
+}
+
+func (l *Lexer) lexHexEscape(value *strings.Builder, digits int) error {
+	if l.pos+digits > len(l.src) {
+		return l.errorf("invalid hex escape")
+	}
+	r, err := strconv.ParseUint(string(l.src[l.pos:l.pos+digits]), 16, 32)
+	if err != nil {
+		return l.errorf("invalid hex escape")
+	}
+	value.WriteRune(rune(r))
+	l.pos += digits
+	return nil
+}
+
+// templates are kept raw, substitutions are lexed so braces and strings inside them are skipped properly
+func (l *Lexer) lexTemplate() (Token, error) {
+	l.pos++
+	for l.pos < len(l.src) {
+		switch l.src[l.pos] {
+		case '`':
+			l.pos++
+			return Token{Kind: TemplateToken}, nil
+		case '\\':
+			l.pos += 2
+		case '$':
+			if l.peek(1) != '{' {
+				l.pos++
+				continue
+			}
+			l.pos += 2
+			if err := l.skipSubstitution(); err != nil {
+				return Token{}, err
+			}
+		default:
+			l.pos++
+		}
+	}
+	return Token{}, l.errorf("unterminated template")
+}
+
+// skips the expression of a ${...} substitution, including its closing brace
+func (l *Lexer) skipSubstitution() error {
+	outer := l.prev
+	l.prev = nil
+	defer func() { l.prev = outer }()
+
+	depth := 1
+	for {
+		tok, err := l.Next()
+		if err != nil {
+			return err
+		}
+		switch {
+		case tok.Kind == EOFToken:
+			return l.errorf("unterminated template substitution")
+		case tok.Kind == PunctToken && tok.Text == "{":
+			depth++
+		case tok.Kind == PunctToken && tok.Text == "}":
+			depth--
+			if depth == 0 {
+				return nil
+			}
+		}
+	}
+}
+
+func (l *Lexer) lexRegex() (Token, error) {
+	l.pos++
+	inClass := false
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch {
+		case c == '\\':
+			l.pos += 2
+			continue
+		case c == '\n':
+			return Token{}, l.errorf("unterminated regex")
+		case c == '[':
+			inClass = true
+		case c == ']':
+			inClass = false
+		case c == '/' && !inClass:
+			l.pos++
+			for l.pos < len(l.src) && isIdentPart(l.src[l.pos]) {
+				l.pos++
+			}
+			return Token{Kind: RegexToken}, nil
+		}
+		l.pos++
+	}
+	return Token{}, l.errorf("unterminated regex")
+}
+
+func (l *Lexer) lexNumber() Token {
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		if isIdentPart(c) || c == '.' {
+			l.pos++
+			continue
+		}
+		// exponent sign, e.g. 1e-7
+		if (c == '+' || c == '-') && (l.src[l.pos-1] == 'e' || l.src[l.pos-1] == 'E') && !isHexNumber(l.src, l.pos) {
+			l.pos++
+			continue
+		}
+		break
+	}
+	return Token{Kind: NumberToken}
+}
+
+func (l *Lexer) lexIdent() Token {
+	for l.pos < len(l.src) {
+		if l.src[l.pos] >= utf8.RuneSelf {
+			_, size := utf8.DecodeRune(l.src[l.pos:])
+			l.pos += size
+			continue
+		}
+		if !isIdentPart(l.src[l.pos]) {
+			break
+		}
+		l.pos++
+	}
+	return Token{Kind: IdentToken}
+}
+
+func (l *Lexer) lexPunct() Token {
+	for _, p := range multiPunct {
+		if strings.HasPrefix(string(l.src[l.pos:min(l.pos+len(p), len(l.src))]), p) {
+			l.pos += len(p)
+			return Token{Kind: PunctToken}
+		}
+	}
+	l.pos++
+	return Token{Kind: PunctToken}
+}
+
+func (l *Lexer) peek(offset int) byte {
+	if l.pos+offset >= len(l.src) {
+		return 0
+	}
+	return l.src[l.pos+offset]
+}
+
+func (l *Lexer) errorf(format string, args ...any) error {
+	return fmt.Errorf("offset %d: %s", l.pos, fmt.Sprintf(format, args...))
+}
+
+// checks whether the number ending right before pos is a hex literal, where 'e' is a digit
+func isHexNumber(src []byte, pos int) bool {
+	start := pos
+	for start > 0 && (isIdentPart(src[start-1]) || src[start-1] == '.') {
+		start--
+	}
+	return pos-start > 1 && src[start] == '0' && (src[start+1] == 'x' || src[start+1] == 'X')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= utf8.RuneSelf
+}
+
+func isIdentPart(c byte) bool {
+	return c == '_' || c == '$' || isDigit(c) || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
@@ -0,0 +1,331 @@
+package jsparse
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Ident is an identifier used as a value, e.g. a shorthand property or a variable reference
+type Ident string
+
+// Raw is an expression that is not a literal, kept as its source
+type Raw string
+
+/*
+ParseValue parses the JavaScript value at the start of src and returns it along with the number
+of bytes it spans. Literals are decoded to Go values:
+
+	objects  -> map[string]any
+	arrays   -> []any
+	strings  -> string
+	numbers  -> float64
+	booleans -> bool (including the minified !0 and !1)
+	null     -> nil
+
+Any other expression is returned as Raw, and bare identifiers as Ident.
+*/
+func ParseValue(src []byte) (any, int, error) {
+	p := &parser{lexer: NewLexer(src), src: src}
+	if err := p.advance(); err != nil {
+		return nil, 0, err
+	}
+
+	value, err := p.parseValue()
+	if err != nil {
+		return nil, 0, err
+	}
+	return value, p.lastEnd, nil
+}
+
+type parser struct {
+	lexer   *Lexer
+	src     []byte
+	tok     Token // current token
+	lastEnd int   // end offset of the last consumed token
+}
+
+func (p *parser) advance() error {
+	p.lastEnd = p.tok.End
+	tok, err := p.lexer.Next()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+	return nil
+}
+
+func (p *parser) is(text string) bool {
+	return p.tok.Kind == PunctToken && p.tok.Text == text
+}
+
+func (p *parser) expect(text string) error {
+	if !p.is(text) {
+		return p.errorf("expected %q, found %q", text, p.tok.Text)
+	}
+	return p.advance()
+}
+
+// a value ends at a separator of the enclosing literal
+func (p *parser) atValueEnd() bool {
+	return p.tok.Kind == EOFToken || p.is(",") || p.is("}") || p.is("]") || p.is(")") || p.is(";")
+}
+
+func (p *parser) parseValue() (any, error) {
+	// a separator or the end of input where a value should be, e.g. {a: }
+	if p.atValueEnd() {
+		return nil, p.errorf("expected value, found %s %q", p.tok.Kind, p.tok.Text)
+	}
+	start := p.tok.Start
+
+	value, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.atValueEnd() {
+		return value, nil
+	}
+
+	// the literal is only a part of a larger expression, keep the whole expression raw
+	if err := p.skipExpression(); err != nil {
+		return nil, err
+	}
+	return Raw(strings.TrimSpace(string(p.src[start:p.lastEnd]))), nil
+}
+
+func (p *parser) parsePrimary() (any, error) {
+	tok := p.tok
+	switch {
+	case p.is("{"):
+		return p.parseObject()
+	case p.is("["):
+		return p.parseArray()
+	case tok.Kind == StringToken:
+		return tok.Value, p.advance()
+	case tok.Kind == NumberToken:
+		return p.parseNumber(1)
+	case p.is("-"):
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if p.tok.Kind != NumberToken {
+			return p.parseRaw(tok.Start)
+		}
+		return p.parseNumber(-1)
+	case p.is("!"):
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		// minifiers write true as !0 and false as !1
+		if p.tok.Kind == NumberToken && (p.tok.Text == "0" || p.tok.Text == "1") {
+			value := p.tok.Text == "0"
+			return value, p.advance()
+		}
+		return p.parseRaw(tok.Start)
+	case tok.Kind == IdentToken:
+		switch tok.Text {
+		case "true":
+			return true, p.advance()
+		case "false":
+			return false, p.advance()
+		case "null":
+			return nil, p.advance()
+		case "void":
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			if p.tok.Kind == NumberToken && p.tok.Text == "0" {
+				return nil, p.advance()
+			}
+			return p.parseRaw(tok.Start)
+		}
+		return Ident(tok.Text), p.advance()
+	}
+
+	return p.parseRaw(tok.Start)
+}
+
+func (p *parser) parseNumber(sign float64) (any, error) {
+	text := strings.ReplaceAll(strings.TrimSuffix(p.tok.Text, "n"), "_", "")
+
+	var value float64
+	if n, err := strconv.ParseInt(text, 0, 64); err == nil {
+		value = float64(n)
+	} else if f, err := strconv.ParseFloat(text, 64); err == nil {
+		value = f
+	} else {
+		return nil, p.errorf("invalid number %q", p.tok.Text)
+	}
+	return sign * value, p.advance()
+}
+
+// consumes an expression that started at start and returns its source
+func (p *parser) parseRaw(start int) (any, error) {
+	if err := p.skipExpression(); err != nil {
+		return nil, err
+	}
+	return Raw(strings.TrimSpace(string(p.src[start:p.lastEnd]))), nil
+}
+
+func (p *parser) parseObject() (any, error) {
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+
+	object := map[string]any{}
+	for !p.is("}") {
+		if p.tok.Kind == EOFToken {
+			return nil, p.errorf("unterminated object")
+		}
+
+		if p.is("...") {
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			if err := p.skipExpression(); err != nil {
+				return nil, err
+			}
+		} else if err := p.parseProperty(object); err != nil {
+			return nil, err
+		}
+
+		if p.is(",") {
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		} else if !p.is("}") {
+			return nil, p.errorf("expected \",\" or \"}\", found %q", p.tok.Text)
+		}
+	}
+
+	return object, p.advance()
+}
+
+func (p *parser) parseProperty(object map[string]any) error {
+	var key string
+	switch {
+	case p.tok.Kind == IdentToken || p.tok.Kind == NumberToken:
+		key = p.tok.Text
+	case p.tok.Kind == StringToken:
+		key = p.tok.Value
+	case p.is("["):
+		// computed key, its value is kept but it can not be looked up by name
+		start := p.tok.Start
+		if err := p.skipBalanced(); err != nil {
+			return err
+		}
+		key = string(p.src[start:p.lastEnd])
+		if err := p.skipExpression(); err != nil {
+			return err
+		}
+		object[key] = nil
+		return nil
+	default:
+		return p.errorf("unexpected %s %q in object", p.tok.Kind, p.tok.Text)
+	}
+	start := p.tok.Start
+	if err := p.advance(); err != nil {
+		return err
+	}
+
+	switch {
+	case p.is(":"):
+		if err := p.advance(); err != nil {
+			return err
+		}
+		value, err := p.parseValue()
+		if err != nil {
+			return err
+		}
+		object[key] = value
+	case p.is(",") || p.is("}"):
+		object[key] = Ident(key)
+	default:
+		// methods, getters and setters
+		if err := p.skipExpression(); err != nil {
+			return err
+		}
+		object[key] = Raw(strings.TrimSpace(string(p.src[start:p.lastEnd])))
+	}
+	return nil
+}
+
+func (p *parser) parseArray() (any, error) {
+	if err := p.expect("["); err != nil {
+		return nil, err
+	}
+
+	array := []any{}
+	for !p.is("]") {
+		if p.tok.Kind == EOFToken {
+			return nil, p.errorf("unterminated array")
+		}
+
+		// holes, e.g. [,1]
+		if p.is(",") {
+			array = append(array, nil)
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		array = append(array, value)
+
+		if p.is(",") {
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		} else if !p.is("]") {
+			return nil, p.errorf("expected \",\" or \"]\", found %q", p.tok.Text)
+		}
+	}
+
+	return array, p.advance()
+}
+
+// skips tokens until a separator at the current nesting depth
+func (p *parser) skipExpression() error {
+	for !p.atValueEnd() {
+		if p.is("{") || p.is("[") || p.is("(") {
+			if err := p.skipBalanced(); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := p.advance(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// skips a bracketed group, including its closing bracket
+func (p *parser) skipBalanced() error {
+	depth := 0
+	for {
+		switch {
+		case p.tok.Kind == EOFToken:
+			return p.errorf("unbalanced brackets")
+		case p.is("{") || p.is("[") || p.is("("):
+			depth++
+		case p.is("}") || p.is("]") || p.is(")"):
+			depth--
+		}
+		if err := p.advance(); err != nil {
+			return err
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("offset %d: %s", p.tok.Start, fmt.Sprintf(format, args...))
+}
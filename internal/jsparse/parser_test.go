@@ -0,0 +1,61 @@
+package jsparse
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseValue(t *testing.T) {
+	tests := []struct {
+		src  string
+		want any
+	}{
+		{`{queryId:"abc",operationName:"UserByScreenName"}`, map[string]any{"queryId": "abc", "operationName": "UserByScreenName"}},
+		{`{a:"}",b:'{'}`, map[string]any{"a": "}", "b": "{"}},
+		{`[1,,-2,!0,!1,void 0,null]`, []any{1.0, nil, -2.0, true, false, nil, nil}},
+		{`{a:/}]/g,b:1}`, map[string]any{"a": Raw("/}]/g"), "b": 1.0}},
+		{"{a:`}${{b:1}}`,c:x}", map[string]any{"a": Raw("`}${{b:1}}`"), "c": Ident("x")}},
+		{`{a,b:c+1,...d}`, map[string]any{"a": Ident("a"), "b": Raw("c+1")}},
+	}
+
+	for _, test := range tests {
+		got, _, err := ParseValue([]byte(test.src))
+		if err != nil {
+			t.Errorf("ParseValue(%q): %v", test.src, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("ParseValue(%q) = %#v, want %#v", test.src, got, test.want)
+		}
+	}
+}
+
+func TestParseValueMissingValue(t *testing.T) {
+	for _, src := range []string{"", "//0", "/*x*/", "{a: }", `{queryId:"x",b: ,c:1}`, ")"} {
+		if _, _, err := ParseValue([]byte(src)); err == nil {
+			t.Errorf("ParseValue(%q) succeeded, want an error", src)
+		}
+	}
+}
+
+func FuzzParseValue(f *testing.F) {
+	for _, seed := range []string{
+		`{queryId:"abc",operationName:"x",metadata:{featureSwitches:["a","b"]}}`,
+		`{a:"}",b:'{\'}'}`,
+		`{a:/[}\]]/g,b:/\//}`,
+		"{a:`${b}}`,c:`${`${d}`}`}",
+		`[1,,2,.5,0x1e-1,1e-7]`,
+		`//0`,
+		`/*x*/`,
+		`{a: }`,
+	} {
+		f.Add([]byte(seed))
+	}
+
+	f.Fuzz(func(t *testing.T, src []byte) {
+		_, n, err := ParseValue(src)
+		if err == nil && (n < 0 || n > len(src)) {
+			t.Fatalf("ParseValue(%q) spans %d bytes of %d", src, n, len(src))
+		}
+	})
+}
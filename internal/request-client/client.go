@@ -1,6 +1,8 @@
 package requestClient
 
 import (
+	"context"
+	"io"
 	"net/http"
 	"time"
 )
@@ -43,12 +45,23 @@ func NewClient(userAgent string, headers map[string]string, cookies map[string]s
 func (c *RequestClient) MakeRequest(
 	method, url string, /* TODO: add fields for payload, and another additional data */
 ) (*Response, error) {
-	req, err := http.NewRequest(method, url, nil)
+	return c.MakeRequestWithContext(context.Background(), method, url, nil, nil)
+}
+
+// runs a request with a body and per-request headers, on top of the client's headers and cookies
+func (c *RequestClient) MakeRequestWithContext(
+	ctx context.Context, method, url string, body io.Reader, headers map[string]string,
+) (*Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header = *c.Headers
+	// clone, so per-request headers never leak into the client's headers
+	req.Header = c.Headers.Clone()
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
 
 	// update current client's cookies for the upcoming request
 	for k, v := range c.Cookies {
@@ -62,6 +75,7 @@ func (c *RequestClient) MakeRequest(
 	if err != nil {
 		return nil, err
 	}
+	defer res.Body.Close()
 
 	return ResponseFromHttp(res)
 }
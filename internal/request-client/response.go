@@ -3,16 +3,22 @@ package requestClient
 import (
 	"io"
 	"net/http"
+	"time"
 )
 
 type Response struct {
-	payload string
-	status  int
+	payload    string
+	status     int
+	date       string    // Date header, used to estimate the server's clock
+	receivedAt time.Time // local time the response headers were received at
 }
 
 // fetching a response's data to a new readable struct
 func ResponseFromHttp(res *http.Response) (*Response, error) {
-	response := &Response{}
+	response := &Response{
+		date:       res.Header.Get("Date"),
+		receivedAt: time.Now(),
+	}
 	payload, err := io.ReadAll(res.Body)
 	if err != nil {
 		return nil, err
@@ -23,3 +29,23 @@ func ResponseFromHttp(res *http.Response) (*Response, error) {
 
 	return response, nil
 }
+
+// returns the response's body
+func (r *Response) Payload() string {
+	return r.payload
+}
+
+// returns the response's status code
+func (r *Response) Status() int {
+	return r.status
+}
+
+// returns the response's Date header
+func (r *Response) Date() string {
+	return r.date
+}
+
+// returns the local time the response was received at
+func (r *Response) ReceivedAt() time.Time {
+	return r.receivedAt
+}
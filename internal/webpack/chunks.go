@@ -0,0 +1,123 @@
+package webpack
+
+import (
+	"errors"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var ErrChunkMapNotFound = errors.New("webpack chunk map not found")
+
+var (
+	// ({<id>:"<name>",...}[e]||e)+"."+{<id>:"<hash>",...}[e]+"<suffix>"
+	idChunkMapRegex = regexp.MustCompile(`\(\{([^{}]*)\}\[\w+\]\|\|\w+\)\+"\."\+\{([^{}]*)\}\[\w+\]\+"([^"]*)"`)
+	// e+"."+{"<name>":"<hash>",...}[e]+"<suffix>"
+	nameChunkMapRegex = regexp.MustCompile(`\w+\+"\."\+\{([^{}]*)\}\[\w+\]\+"([^"]*)"`)
+	entryRegex        = regexp.MustCompile(`"?([\w./~\-]+)"?:"([^"]*)"`)
+)
+
+// Chunk is a lazily loaded script of the webpack bundle
+type Chunk struct {
+	ID   string // chunk id, equal to the name when the runtime keys its map by name
+	Name string
+	Hash string
+}
+
+// ChunkMap holds every chunk the webpack runtime knows how to load
+type ChunkMap struct {
+	Chunks []Chunk
+	Suffix string // appended after the hash when building a chunk's filename, e.g. "a.js"
+}
+
+/*
+This function parses the chunk map out of the webpack runtime, which x.com inlines in its home page.
+Both runtime layouts are supported: a map keyed by chunk name, and an id -> name map next to an id -> hash map.
+The runtime builds stylesheet names the same way, so only a map whose suffix ends in ".js" is accepted.
+*/
+func ParseChunkMap(script string) (*ChunkMap, error) {
+	for _, m := range idChunkMapRegex.FindAllStringSubmatch(script, -1) {
+		if !isScriptSuffix(m[3]) {
+			continue
+		}
+
+		names := parseEntries(m[1])
+		hashes := parseEntries(m[2])
+
+		chunkMap := &ChunkMap{Suffix: m[3]}
+		for id, hash := range hashes {
+			name, ok := names[id]
+			if !ok {
+				name = id
+			}
+			chunkMap.Chunks = append(chunkMap.Chunks, Chunk{ID: id, Name: name, Hash: hash})
+		}
+		chunkMap.sort()
+		return chunkMap, nil
+	}
+
+	for _, m := range nameChunkMapRegex.FindAllStringSubmatch(script, -1) {
+		if !isScriptSuffix(m[2]) {
+			continue
+		}
+
+		chunkMap := &ChunkMap{Suffix: m[2]}
+		for name, hash := range parseEntries(m[1]) {
+			chunkMap.Chunks = append(chunkMap.Chunks, Chunk{ID: name, Name: name, Hash: hash})
+		}
+		chunkMap.sort()
+		return chunkMap, nil
+	}
+
+	return nil, ErrChunkMapNotFound
+}
+
+func isScriptSuffix(suffix string) bool {
+	return strings.HasSuffix(suffix, ".js")
+}
+
+// looks up a chunk by its name
+func (m *ChunkMap) Find(name string) (Chunk, bool) {
+	for _, chunk := range m.Chunks {
+		if chunk.Name == name {
+			return chunk, true
+		}
+	}
+	return Chunk{}, false
+}
+
+// returns the chunk's filename, as the runtime would build it
+func (m *ChunkMap) Filename(chunk Chunk) string {
+	return chunk.Name + "." + chunk.Hash + m.Suffix
+}
+
+// resolves the chunk's url against the url of another script of the same bundle
+func (m *ChunkMap) URL(base string, chunk Chunk) (string, error) {
+	baseUrl, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+
+	ref, err := url.Parse(m.Filename(chunk))
+	if err != nil {
+		return "", err
+	}
+
+	return baseUrl.ResolveReference(ref).String(), nil
+}
+
+func (m *ChunkMap) sort() {
+	sort.Slice(m.Chunks, func(i, j int) bool {
+		return m.Chunks[i].Name < m.Chunks[j].Name
+	})
+}
+
+// parses the entries of a flat object literal whose values are strings
+func parseEntries(body string) map[string]string {
+	entries := map[string]string{}
+	for _, m := range entryRegex.FindAllStringSubmatch(body, -1) {
+		entries[m[1]] = m[2]
+	}
+	return entries
+}
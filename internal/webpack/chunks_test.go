@@ -0,0 +1,92 @@
+package webpack
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseChunkMap(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   *ChunkMap
+	}{
+		{
+			name:   "name map",
+			script: `o.u=e=>e+"."+{"bundle.Profile":"def456",vendor:"abc123"}[e]+"a.js"`,
+			want: &ChunkMap{Suffix: "a.js", Chunks: []Chunk{
+				{ID: "bundle.Profile", Name: "bundle.Profile", Hash: "def456"},
+				{ID: "vendor", Name: "vendor", Hash: "abc123"},
+			}},
+		},
+		{
+			name:   "id map",
+			script: `o.u=e=>({12:"bundle.Profile",34:"vendor"}[e]||e)+"."+{12:"def456",34:"abc123",56:"0011ff"}[e]+"a.js"`,
+			want: &ChunkMap{Suffix: "a.js", Chunks: []Chunk{
+				{ID: "56", Name: "56", Hash: "0011ff"},
+				{ID: "12", Name: "bundle.Profile", Hash: "def456"},
+				{ID: "34", Name: "vendor", Hash: "abc123"},
+			}},
+		},
+		{
+			name: "name map after css map",
+			script: `o.miniCssF=e=>e+"."+{"bundle.Profile":"cccccc"}[e]+"a.css";` +
+				`o.u=e=>e+"."+{"bundle.Profile":"def456"}[e]+"a.js"`,
+			want: &ChunkMap{Suffix: "a.js", Chunks: []Chunk{
+				{ID: "bundle.Profile", Name: "bundle.Profile", Hash: "def456"},
+			}},
+		},
+		{
+			name: "id map after css map",
+			script: `o.miniCssF=e=>({12:"bundle.Profile"}[e]||e)+"."+{12:"cccccc"}[e]+"a.css";` +
+				`o.u=e=>({12:"bundle.Profile"}[e]||e)+"."+{12:"def456"}[e]+"a.js"`,
+			want: &ChunkMap{Suffix: "a.js", Chunks: []Chunk{
+				{ID: "12", Name: "bundle.Profile", Hash: "def456"},
+			}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ParseChunkMap(test.script)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseChunkMapNotFound(t *testing.T) {
+	for _, script := range []string{
+		"",
+		`o.miniCssF=e=>e+"."+{"bundle.Profile":"cccccc"}[e]+"a.css"`,
+	} {
+		if _, err := ParseChunkMap(script); !errors.Is(err, ErrChunkMapNotFound) {
+			t.Errorf("ParseChunkMap(%q) returned %v, want %v", script, err, ErrChunkMapNotFound)
+		}
+	}
+}
+
+func TestChunkMapURL(t *testing.T) {
+	chunkMap, err := ParseChunkMap(`e+"."+{"bundle.Profile":"def456"}[e]+"a.js"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chunk, ok := chunkMap.Find("bundle.Profile")
+	if !ok {
+		t.Fatal("chunk not found")
+	}
+
+	got, err := chunkMap.URL("https://abs.twimg.com/responsive-web/client-web/main.abc123a.js", chunk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "https://abs.twimg.com/responsive-web/client-web/bundle.Profile.def456a.js"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,152 @@
+package operations
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+/*
+This type represents a scraped state of x.com's operations, as stored on disk by a Cache.
+*/
+type Snapshot struct {
+	MainScriptURL  string      `json:"mainScriptUrl"`
+	MainScriptHash string      `json:"mainScriptHash"` // sha256 of the main script's content
+	FetchedAt      time.Time   `json:"fetchedAt"`
+	Operations     []Operation `json:"operations"`
+	BearerToken    string      `json:"bearerToken"`   // public bearer token of the web client
+	RESTEndpoints  []string    `json:"restEndpoints"` // REST paths (/1.1/..., /2/...) used by the web client
+}
+
+/*
+Cache keeps a Snapshot in a JSON file. A cached snapshot is reused as long as it is younger
+than TTL and the home page still references the same main script.
+*/
+type Cache struct {
+	Path    string
+	TTL     time.Duration
+	Options Options // configures how new snapshots are scraped
+}
+
+// initiates a new cache stored at the given path
+func NewCache(path string, ttl time.Duration) *Cache {
+	return &Cache{Path: path, TTL: ttl}
+}
+
+// Load reads the cached snapshot, the error wraps os.ErrNotExist when nothing was cached yet.
+func (c *Cache) Load() (*Snapshot, error) {
+	data, err := os.ReadFile(c.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// Save writes the snapshot to the cache file, replacing it atomically.
+func (c *Cache) Save(snapshot *Snapshot) error {
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.Path), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(c.Path), filepath.Base(c.Path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), c.Path)
+}
+
+// LoadOperationsFile reads a JSON file holding either a Snapshot or a plain list of operations.
+func LoadOperationsFile(path string) ([]Operation, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var ops []Operation
+	if err := json.Unmarshal(data, &ops); err == nil {
+		return ops, nil
+	}
+
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("%s: not an operations snapshot: %w", path, err)
+	}
+	return snapshot.Operations, nil
+}
+
+// GetOperations returns the cached operations, scraping and saving a new snapshot when the cached one is stale.
+func (c *Cache) GetOperations(ctx context.Context) ([]Operation, error) {
+	snapshot, err := c.GetSnapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return snapshot.Operations, nil
+}
+
+// GetSnapshot returns the cached snapshot, scraping and saving a new one when the cached one is stale.
+func (c *Cache) GetSnapshot(ctx context.Context) (*Snapshot, error) {
+	s := newScraper(ctx, c.Options)
+
+	mainPageContent, err := s.getMainPage()
+	if err != nil {
+		return nil, err
+	}
+
+	mainScriptUrl, err := s.getMainScriptUrl(mainPageContent)
+	if err != nil {
+		return nil, err
+	}
+
+	// an unreadable cache is treated like a missing one, it gets overwritten below
+	cached, err := c.Load()
+	if err == nil && c.isFresh(cached, mainScriptUrl) {
+		return cached, nil
+	}
+
+	snapshot, err := s.scrapeSnapshot(mainPageContent, c.Options.Overrides)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.Save(snapshot); err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// checks whether a snapshot can still be used for the currently deployed main script
+func (c *Cache) isFresh(snapshot *Snapshot, mainScriptUrl string) bool {
+	return snapshot.MainScriptURL == mainScriptUrl && time.Since(snapshot.FetchedAt) < c.TTL
+}
+
+// hashContent returns the hex encoded sha256 of a script's content.
+func hashContent(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
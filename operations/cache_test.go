@@ -0,0 +1,192 @@
+package operations
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// a mapFetcher counting how many times each url was fetched
+type countingFetcher struct {
+	pages mapFetcher
+
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func (f *countingFetcher) Fetch(ctx context.Context, url string) (string, error) {
+	f.mu.Lock()
+	if f.counts == nil {
+		f.counts = map[string]int{}
+	}
+	f.counts[url]++
+	f.mu.Unlock()
+	return f.pages.Fetch(ctx, url)
+}
+
+func (f *countingFetcher) count(url string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.counts[url]
+}
+
+func newTestCache(t *testing.T, ttl time.Duration, fetcher Fetcher) *Cache {
+	t.Helper()
+
+	cache := NewCache(filepath.Join(t.TempDir(), "cache", "operations.json"), ttl)
+	cache.Options.Fetcher = fetcher
+	return cache
+}
+
+func TestCacheReusesFreshSnapshot(t *testing.T) {
+	fetcher := &countingFetcher{pages: watcherPages("key", "main.a.js", testOperation("q1", "Op"))}
+	cache := newTestCache(t, time.Hour, fetcher)
+
+	first, err := cache.GetSnapshot(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := cache.GetSnapshot(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := fetcher.count(testScriptsURL + "main.a.js"); got != 1 {
+		t.Fatalf("main script fetched %d times, want 1", got)
+	}
+	if second.MainScriptHash != first.MainScriptHash || len(second.Operations) != 1 {
+		t.Fatalf("got %+v, want the cached snapshot", second)
+	}
+}
+
+func TestCacheExpiresAfterTTL(t *testing.T) {
+	fetcher := &countingFetcher{pages: watcherPages("key", "main.a.js", testOperation("q1", "Op"))}
+	cache := newTestCache(t, time.Hour, fetcher)
+
+	snapshot, err := cache.GetSnapshot(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snapshot.FetchedAt = time.Now().Add(-2 * time.Hour)
+	if err := cache.Save(snapshot); err != nil {
+		t.Fatal(err)
+	}
+
+	refreshed, err := cache.GetSnapshot(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := fetcher.count(testScriptsURL + "main.a.js"); got != 2 {
+		t.Fatalf("main script fetched %d times, want 2", got)
+	}
+	if time.Since(refreshed.FetchedAt) > time.Minute {
+		t.Fatalf("got a snapshot fetched at %v, want a new one", refreshed.FetchedAt)
+	}
+}
+
+func TestCacheMainScriptChanged(t *testing.T) {
+	fetcher := &syncFetcher{}
+	fetcher.set(watcherPages("key", "main.a.js", testOperation("q1", "Old")))
+	cache := newTestCache(t, time.Hour, fetcher)
+
+	if _, err := cache.GetSnapshot(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	fetcher.set(watcherPages("key", "main.b.js", testOperation("q2", "New")))
+	snapshot, err := cache.GetSnapshot(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.MainScriptURL != testScriptsURL+"main.b.js" || snapshot.Operations[0].OperationName != "New" {
+		t.Fatalf("got %+v, want the new deployment", snapshot)
+	}
+
+	saved, err := cache.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.MainScriptURL != snapshot.MainScriptURL {
+		t.Fatal("the new snapshot was not saved")
+	}
+}
+
+func TestCacheOverwritesUnreadableFile(t *testing.T) {
+	cache := newTestCache(t, time.Hour, watcherPages("key", "main.a.js", testOperation("q1", "Op")))
+
+	if err := os.MkdirAll(filepath.Dir(cache.Path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(cache.Path, []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := cache.GetSnapshot(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if saved, err := cache.Load(); err != nil || len(saved.Operations) != 1 {
+		t.Fatalf("cache file not overwritten: %v", err)
+	}
+}
+
+func TestCacheLoadMissing(t *testing.T) {
+	cache := newTestCache(t, time.Hour, nil)
+	if _, err := cache.Load(); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("got %v, want os.ErrNotExist", err)
+	}
+}
+
+func TestCacheSaveAtomic(t *testing.T) {
+	cache := newTestCache(t, time.Hour, nil)
+
+	for _, queryID := range []string{"q1", "q2"} {
+		snapshot := &Snapshot{MainScriptURL: "main.js", Operations: []Operation{{QueryID: queryID, OperationName: "Op"}}}
+		if err := cache.Save(snapshot); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	saved, err := cache.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.Operations[0].QueryID != "q2" {
+		t.Fatalf("got %+v, want the latest snapshot", saved.Operations)
+	}
+
+	// the temporary file is renamed over the cache file, nothing is left next to it
+	entries, err := os.ReadDir(filepath.Dir(cache.Path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != filepath.Base(cache.Path) {
+		var names []string
+		for _, entry := range entries {
+			names = append(names, entry.Name())
+		}
+		t.Fatalf("got files %v, want only the cache file", names)
+	}
+}
+
+func TestCacheSaveFailureKeepsFile(t *testing.T) {
+	cache := newTestCache(t, time.Hour, nil)
+	if err := cache.Save(&Snapshot{MainScriptURL: "main.js"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// a snapshot that cannot be encoded must not truncate the previous one
+	broken := &Snapshot{Operations: []Operation{{VariableDefaults: map[string]any{"f": func() {}}}}}
+	if err := cache.Save(broken); err == nil {
+		t.Fatal("saving an unencodable snapshot succeeded")
+	}
+
+	saved, err := cache.Load()
+	if err != nil || saved.MainScriptURL != "main.js" {
+		t.Fatalf("previous snapshot lost: %v", err)
+	}
+}
@@ -0,0 +1,157 @@
+package operations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/nitayStain/x-aio/internal/webpack"
+)
+
+const (
+	mainSource     = "main"
+	defaultWorkers = 8
+)
+
+// ChunkOptions configures which webpack chunks GetAllOperations scrapes, and how
+type ChunkOptions struct {
+	Options
+	Workers int                    // maximum number of chunks fetched at once, defaults to 8
+	Filter  func(name string) bool // chooses the chunks to scrape by name, every chunk when nil
+}
+
+// ChunkError tells which webpack chunk failed to be scraped
+type ChunkError struct {
+	Chunk string
+	Err   error
+}
+
+func (e *ChunkError) Error() string {
+	return fmt.Sprintf("chunk %s: %v", e.Chunk, e.Err)
+}
+
+func (e *ChunkError) Unwrap() error {
+	return e.Err
+}
+
+/*
+GetAllOperations retrieves the operations of main.js and of every lazily loaded webpack chunk
+(bundle.*, ondemand.*, loader.*...) chosen by the options. Operations defined in more than one
+script are only returned once, main.js taking precedence.
+
+A chunk that fails does not fail the others: the operations that were scraped are returned along
+with an error joining a *ChunkError per failed chunk. The operations are nil only when main.js
+itself could not be scraped.
+*/
+func GetAllOperations(ctx context.Context, opts ChunkOptions) ([]Operation, error) {
+	s := newScraper(ctx, opts.Options)
+
+	mainPageContent, err := s.getMainPage()
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, err := s.scrapeSnapshot(mainPageContent, opts.Overrides)
+	if err != nil {
+		return nil, err
+	}
+
+	// without a chunk map only main.js can be scraped, its operations are still returned
+	chunkMap, err := webpack.ParseChunkMap(mainPageContent)
+	if err != nil {
+		return snapshot.Operations, err
+	}
+
+	var chunks []webpack.Chunk
+	for _, chunk := range chunkMap.Chunks {
+		if opts.Filter == nil || opts.Filter(chunk.Name) {
+			chunks = append(chunks, chunk)
+		}
+	}
+
+	chunkOps, chunkErr := s.scrapeChunks(chunkMap, chunks, snapshot.MainScriptURL, opts.Workers)
+
+	defaults := parseFeatureDefaults(mainPageContent)
+	for i := range chunkOps {
+		chunkOps[i].Features = ResolveFeatures(chunkOps[i].FeatureSwitches, defaults, opts.Overrides)
+	}
+
+	return mergeOperations(snapshot.Operations, chunkOps), chunkErr
+}
+
+/*
+scrapeChunks fetches the given chunks with a bounded amount of workers and parses their operations.
+The operations of the chunks that succeeded are returned even when others failed.
+*/
+func (s *scraper) scrapeChunks(chunkMap *webpack.ChunkMap, chunks []webpack.Chunk, baseUrl string, workers int) ([]Operation, error) {
+	if workers <= 0 {
+		workers = defaultWorkers
+	}
+
+	results := make([][]Operation, len(chunks))
+	errs := make([]error, len(chunks))
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i], errs[i] = s.scrapeChunk(chunkMap, chunks[i], baseUrl)
+			}
+		}()
+	}
+
+	for i := range chunks {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	var ops []Operation
+	for _, chunkOps := range results {
+		ops = append(ops, chunkOps...)
+	}
+	return ops, errors.Join(errs...)
+}
+
+// scrapeChunk fetches a single chunk and parses its operations.
+func (s *scraper) scrapeChunk(chunkMap *webpack.ChunkMap, chunk webpack.Chunk, baseUrl string) ([]Operation, error) {
+	chunkUrl, err := chunkMap.URL(baseUrl, chunk)
+	if err != nil {
+		return nil, &ChunkError{Chunk: chunk.Name, Err: err}
+	}
+
+	content, err := s.fetcher.Fetch(s.ctx, chunkUrl)
+	if err != nil {
+		return nil, &ChunkError{Chunk: chunk.Name, Err: err}
+	}
+
+	ops := parseOperations([]byte(content))
+	for i := range ops {
+		ops[i].Source = chunk.Name
+	}
+	return ops, nil
+}
+
+/*
+mergeOperations concatenates operation lists, dropping any operation whose name was already seen,
+so the first list wins even when a later one holds the operation with another query id.
+*/
+func mergeOperations(lists ...[]Operation) []Operation {
+	seen := map[string]bool{}
+
+	var merged []Operation
+	for _, ops := range lists {
+		for _, op := range ops {
+			if seen[op.OperationName] {
+				continue
+			}
+			seen[op.OperationName] = true
+			merged = append(merged, op)
+		}
+	}
+	return merged
+}
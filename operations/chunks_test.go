@@ -0,0 +1,95 @@
+package operations
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/nitayStain/x-aio/internal/webpack"
+)
+
+// serves pages from a map, anything else is answered with a 404
+type mapFetcher map[string]string
+
+func (f mapFetcher) Fetch(ctx context.Context, url string) (string, error) {
+	content, ok := f[url]
+	if !ok {
+		return "", &HTTPError{URL: url, StatusCode: http.StatusNotFound}
+	}
+	return content, nil
+}
+
+const (
+	testScriptsURL = "https://abs.twimg.com/responsive-web/client-web/"
+	testHomePage   = `<html><head>` +
+		`<link rel="preload" as="script" crossorigin="anonymous" href="` + testScriptsURL + `main.abc123.js">` +
+		`<script>window.__INITIAL_STATE__={"featureSwitch":{"defaultConfig":{"a":{"value":true}}}};</script>` +
+		`<script>s.u=e=>e+"."+{"bundle.Ok":"h1","bundle.Missing":"h2"}[e]+"a.js"</script>` +
+		`</head></html>`
+)
+
+func testOperation(queryID, name string) string {
+	return `{queryId:"` + queryID + `",operationName:"` + name + `",operationType:"query",metadata:{featureSwitches:["a"],fieldToggles:[]}}`
+}
+
+func TestGetAllOperationsPartialFailure(t *testing.T) {
+	fetcher := mapFetcher{
+		"https://x.com":                     testHomePage,
+		testScriptsURL + "main.abc123.js":   `e.exports=` + testOperation("q1", "Main"),
+		testScriptsURL + "bundle.Ok.h1a.js": `e.exports=` + testOperation("q2", "FromChunk"),
+	}
+
+	ops, err := GetAllOperations(context.Background(), ChunkOptions{Options: Options{Fetcher: fetcher}})
+
+	var chunkErr *ChunkError
+	if !errors.As(err, &chunkErr) || chunkErr.Chunk != "bundle.Missing" {
+		t.Fatalf("got error %v, want a ChunkError for bundle.Missing", err)
+	}
+
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("got error %v, want it to wrap the 404", err)
+	}
+
+	var names []string
+	for _, op := range ops {
+		names = append(names, op.OperationName)
+		if !op.Features["a"] {
+			t.Errorf("%s: feature defaults not resolved: %v", op.OperationName, op.Features)
+		}
+	}
+	sort.Strings(names)
+	if len(names) != 2 || names[0] != "FromChunk" || names[1] != "Main" {
+		t.Fatalf("got operations %v, want the ones of main.js and bundle.Ok", names)
+	}
+}
+
+func TestGetAllOperationsWithoutChunkMap(t *testing.T) {
+	fetcher := mapFetcher{
+		"https://x.com":                   `<link rel="preload" as="script" href="` + testScriptsURL + `main.abc123.js">`,
+		testScriptsURL + "main.abc123.js": `e.exports=` + testOperation("q1", "Main"),
+	}
+
+	ops, err := GetAllOperations(context.Background(), ChunkOptions{Options: Options{Fetcher: fetcher}})
+	if !errors.Is(err, webpack.ErrChunkMapNotFound) {
+		t.Fatalf("got error %v, want ErrChunkMapNotFound", err)
+	}
+	if len(ops) != 1 || ops[0].OperationName != "Main" {
+		t.Fatalf("got operations %+v, want the ones of main.js", ops)
+	}
+}
+
+func TestMergeOperations(t *testing.T) {
+	main := []Operation{{OperationName: "A", QueryID: "main"}, {OperationName: "B", QueryID: "main"}}
+	chunk := []Operation{{OperationName: "A", QueryID: "chunk"}, {OperationName: "C", QueryID: "chunk"}, {OperationName: "C", QueryID: "other"}}
+
+	merged := mergeOperations(main, chunk)
+
+	want := []Operation{{OperationName: "A", QueryID: "main"}, {OperationName: "B", QueryID: "main"}, {OperationName: "C", QueryID: "chunk"}}
+	if !reflect.DeepEqual(merged, want) {
+		t.Fatalf("got %+v, want %+v", merged, want)
+	}
+}
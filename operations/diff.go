@@ -0,0 +1,153 @@
+package operations
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// OperationChange describes how a single operation changed between two snapshots
+type OperationChange struct {
+	OperationName          string   `json:"operationName"`
+	OldQueryID             string   `json:"oldQueryId,omitempty"` // only set when the query id changed
+	NewQueryID             string   `json:"newQueryId,omitempty"`
+	AddedFeatureSwitches   []string `json:"addedFeatureSwitches,omitempty"`
+	RemovedFeatureSwitches []string `json:"removedFeatureSwitches,omitempty"`
+	AddedFieldToggles      []string `json:"addedFieldToggles,omitempty"`
+	RemovedFieldToggles    []string `json:"removedFieldToggles,omitempty"`
+}
+
+// Changes is the result of comparing two lists of operations
+type Changes struct {
+	Added   []Operation       `json:"added"`
+	Removed []Operation       `json:"removed"`
+	Changed []OperationChange `json:"changed"`
+}
+
+// Diff compares two lists of operations by their names.
+func Diff(before, after []Operation) *Changes {
+	beforeByName := indexByName(before)
+	afterByName := indexByName(after)
+
+	changes := &Changes{
+		Added:   []Operation{},
+		Removed: []Operation{},
+		Changed: []OperationChange{},
+	}
+
+	for _, name := range sortedKeys(afterByName) {
+		if _, ok := beforeByName[name]; !ok {
+			changes.Added = append(changes.Added, afterByName[name])
+		}
+	}
+
+	for _, name := range sortedKeys(beforeByName) {
+		old := beforeByName[name]
+		cur, ok := afterByName[name]
+		if !ok {
+			changes.Removed = append(changes.Removed, old)
+			continue
+		}
+
+		change := OperationChange{OperationName: name}
+		if old.QueryID != cur.QueryID {
+			change.OldQueryID = old.QueryID
+			change.NewQueryID = cur.QueryID
+		}
+		change.AddedFeatureSwitches, change.RemovedFeatureSwitches = diffLists(old.FeatureSwitches, cur.FeatureSwitches)
+		change.AddedFieldToggles, change.RemovedFieldToggles = diffLists(old.FieldToggles, cur.FieldToggles)
+
+		if !change.empty() {
+			changes.Changed = append(changes.Changed, change)
+		}
+	}
+
+	return changes
+}
+
+// reports whether the two lists were identical
+func (c *Changes) Empty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Changed) == 0
+}
+
+// formats the changes as human readable text
+func (c *Changes) String() string {
+	if c.Empty() {
+		return "no changes\n"
+	}
+
+	var b strings.Builder
+	for _, op := range c.Added {
+		fmt.Fprintf(&b, "+ %s (%s, %s)\n", op.OperationName, op.OperationType, op.QueryID)
+	}
+	for _, op := range c.Removed {
+		fmt.Fprintf(&b, "- %s (%s, %s)\n", op.OperationName, op.OperationType, op.QueryID)
+	}
+	for _, change := range c.Changed {
+		fmt.Fprintf(&b, "~ %s\n", change.OperationName)
+		if change.OldQueryID != "" || change.NewQueryID != "" {
+			fmt.Fprintf(&b, "    queryId: %s -> %s\n", change.OldQueryID, change.NewQueryID)
+		}
+		writeList(&b, "+ feature switch", change.AddedFeatureSwitches)
+		writeList(&b, "- feature switch", change.RemovedFeatureSwitches)
+		writeList(&b, "+ field toggle", change.AddedFieldToggles)
+		writeList(&b, "- field toggle", change.RemovedFieldToggles)
+	}
+	return b.String()
+}
+
+func (c OperationChange) empty() bool {
+	return c.OldQueryID == "" && c.NewQueryID == "" &&
+		len(c.AddedFeatureSwitches) == 0 && len(c.RemovedFeatureSwitches) == 0 &&
+		len(c.AddedFieldToggles) == 0 && len(c.RemovedFieldToggles) == 0
+}
+
+func writeList(b *strings.Builder, label string, items []string) {
+	for _, item := range items {
+		fmt.Fprintf(b, "    %s: %s\n", label, item)
+	}
+}
+
+// returns the items that only exist in after, and the items that only exist in before
+func diffLists(before, after []string) ([]string, []string) {
+	inBefore := map[string]bool{}
+	for _, item := range before {
+		inBefore[item] = true
+	}
+	inAfter := map[string]bool{}
+	for _, item := range after {
+		inAfter[item] = true
+	}
+
+	var added, removed []string
+	for _, item := range after {
+		if !inBefore[item] {
+			added = append(added, item)
+		}
+	}
+	for _, item := range before {
+		if !inAfter[item] {
+			removed = append(removed, item)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
+
+func indexByName(ops []Operation) map[string]Operation {
+	byName := make(map[string]Operation, len(ops))
+	for _, op := range ops {
+		byName[op.OperationName] = op
+	}
+	return byName
+}
+
+func sortedKeys(m map[string]Operation) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
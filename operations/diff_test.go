@@ -0,0 +1,148 @@
+package operations
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	op := func(queryID, name string, featureSwitches, fieldToggles []string) Operation {
+		return Operation{QueryID: queryID, OperationName: name, OperationType: "query", FeatureSwitches: featureSwitches, FieldToggles: fieldToggles}
+	}
+
+	tests := []struct {
+		name   string
+		before []Operation
+		after  []Operation
+		want   *Changes
+	}{
+		{
+			name:   "no changes",
+			before: []Operation{op("q1", "A", []string{"f1"}, nil)},
+			after:  []Operation{op("q1", "A", []string{"f1"}, nil)},
+			want:   &Changes{Added: []Operation{}, Removed: []Operation{}, Changed: []OperationChange{}},
+		},
+		{
+			name:   "added and removed",
+			before: []Operation{op("q1", "A", nil, nil), op("q2", "C", nil, nil)},
+			after:  []Operation{op("q3", "D", nil, nil), op("q1", "A", nil, nil), op("q4", "B", nil, nil)},
+			want: &Changes{
+				Added:   []Operation{op("q4", "B", nil, nil), op("q3", "D", nil, nil)},
+				Removed: []Operation{op("q2", "C", nil, nil)},
+				Changed: []OperationChange{},
+			},
+		},
+		{
+			name:   "query id changed",
+			before: []Operation{op("q1", "A", nil, nil)},
+			after:  []Operation{op("q2", "A", nil, nil)},
+			want: &Changes{
+				Added:   []Operation{},
+				Removed: []Operation{},
+				Changed: []OperationChange{{OperationName: "A", OldQueryID: "q1", NewQueryID: "q2"}},
+			},
+		},
+		{
+			name:   "feature switches and field toggles changed",
+			before: []Operation{op("q1", "A", []string{"kept", "removed", "also_removed"}, []string{"toggle_removed"})},
+			after:  []Operation{op("q1", "A", []string{"kept", "new_b", "new_a"}, []string{"toggle_added"})},
+			want: &Changes{
+				Added:   []Operation{},
+				Removed: []Operation{},
+				Changed: []OperationChange{{
+					OperationName:          "A",
+					AddedFeatureSwitches:   []string{"new_a", "new_b"},
+					RemovedFeatureSwitches: []string{"also_removed", "removed"},
+					AddedFieldToggles:      []string{"toggle_added"},
+					RemovedFieldToggles:    []string{"toggle_removed"},
+				}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := Diff(test.before, test.after)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("got %+v, want %+v", got, test.want)
+			}
+			if got.Empty() != (test.name == "no changes") {
+				t.Fatalf("Empty() = %v", got.Empty())
+			}
+		})
+	}
+}
+
+func TestChangesString(t *testing.T) {
+	tests := []struct {
+		name    string
+		changes *Changes
+		want    string
+	}{
+		{
+			name:    "no changes",
+			changes: Diff(nil, nil),
+			want:    "no changes\n",
+		},
+		{
+			name: "every kind of change",
+			changes: &Changes{
+				Added:   []Operation{{QueryID: "q3", OperationName: "New", OperationType: "mutation"}},
+				Removed: []Operation{{QueryID: "q2", OperationName: "Old", OperationType: "query"}},
+				Changed: []OperationChange{
+					{OperationName: "Moved", OldQueryID: "q1", NewQueryID: "q4"},
+					{
+						OperationName:          "Toggled",
+						AddedFeatureSwitches:   []string{"f_added"},
+						RemovedFeatureSwitches: []string{"f_removed"},
+						AddedFieldToggles:      []string{"t_added"},
+						RemovedFieldToggles:    []string{"t_removed"},
+					},
+				},
+			},
+			want: "+ New (mutation, q3)\n" +
+				"- Old (query, q2)\n" +
+				"~ Moved\n" +
+				"    queryId: q1 -> q4\n" +
+				"~ Toggled\n" +
+				"    + feature switch: f_added\n" +
+				"    - feature switch: f_removed\n" +
+				"    + field toggle: t_added\n" +
+				"    - field toggle: t_removed\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.changes.String(); got != test.want {
+				t.Fatalf("got\n%s\nwant\n%s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestChangesJSON(t *testing.T) {
+	changes := Diff(
+		[]Operation{{QueryID: "q1", OperationName: "A", FeatureSwitches: []string{"f1"}}},
+		[]Operation{{QueryID: "q2", OperationName: "A", FeatureSwitches: []string{"f1", "f2"}}},
+	)
+
+	data, err := json.Marshal(changes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"added":[],"removed":[],"changed":[{"operationName":"A","oldQueryId":"q1","newQueryId":"q2","addedFeatureSwitches":["f2"]}]}`
+	if string(data) != want {
+		t.Fatalf("got %s, want %s", data, want)
+	}
+
+	data, err = json.Marshal(Diff(nil, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"added":[],"removed":[],"changed":[]}`; string(data) != want {
+		t.Fatalf("got %s, want %s", data, want)
+	}
+}
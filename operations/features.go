@@ -0,0 +1,61 @@
+package operations
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+var initialStateRegex = regexp.MustCompile(`window\.__INITIAL_STATE__\s*=\s*`)
+
+// the part of window.__INITIAL_STATE__ that holds the feature switches
+type initialState struct {
+	FeatureSwitch struct {
+		DefaultConfig map[string]struct {
+			Value any `json:"value"`
+		} `json:"defaultConfig"`
+	} `json:"featureSwitch"`
+}
+
+/*
+This function extracts the default feature switch values from the window.__INITIAL_STATE__
+object that x.com embeds in its home page. Only boolean switches are kept, since those are
+the only ones the GraphQL api expects in its features object.
+
+A page without a readable initial state simply has no defaults, the operations can still be
+scraped without them, so nil is returned rather than an error.
+*/
+func parseFeatureDefaults(html string) map[string]bool {
+	loc := initialStateRegex.FindStringIndex(html)
+	if loc == nil {
+		return nil
+	}
+
+	var state initialState
+	decoder := json.NewDecoder(strings.NewReader(html[loc[1]:]))
+	if err := decoder.Decode(&state); err != nil {
+		return nil
+	}
+
+	defaults := make(map[string]bool, len(state.FeatureSwitch.DefaultConfig))
+	for name, entry := range state.FeatureSwitch.DefaultConfig {
+		if value, ok := entry.Value.(bool); ok {
+			defaults[name] = value
+		}
+	}
+
+	return defaults
+}
+
+// ResolveFeatures maps every switch name to its value: overrides first, then defaults, otherwise false.
+func ResolveFeatures(switches []string, defaults, overrides map[string]bool) map[string]bool {
+	resolved := make(map[string]bool, len(switches))
+	for _, name := range switches {
+		if value, ok := overrides[name]; ok {
+			resolved[name] = value
+			continue
+		}
+		resolved[name] = defaults[name]
+	}
+	return resolved
+}
@@ -0,0 +1,46 @@
+package operations
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestParseFeatureDefaults(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want map[string]bool
+	}{
+		{"booleans only", `<script>window.__INITIAL_STATE__ = {"featureSwitch":{"defaultConfig":{"a":{"value":true},"b":{"value":false},"c":{"value":3}}}};</script>`, map[string]bool{"a": true, "b": false}},
+		{"missing", `<html></html>`, nil},
+		{"invalid", `<script>window.__INITIAL_STATE__ = {"featureSwitch":</script>`, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := parseFeatureDefaults(test.html); !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+// query ids are still scraped from a home page without an initial state, every feature being false
+func TestGetSnapshotWithoutInitialState(t *testing.T) {
+	fetcher := mapFetcher{
+		"https://x.com":                   `<link rel="preload" as="script" href="` + testScriptsURL + `main.abc123.js">`,
+		testScriptsURL + "main.abc123.js": `e.exports=` + testOperation("q1", "Main"),
+	}
+
+	snapshot, err := GetSnapshot(context.Background(), Options{Fetcher: fetcher})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshot.Operations) != 1 || snapshot.Operations[0].QueryID != "q1" {
+		t.Fatalf("got operations %+v", snapshot.Operations)
+	}
+	if features := snapshot.Operations[0].Features; !reflect.DeepEqual(features, map[string]bool{"a": false}) {
+		t.Fatalf("got features %v", features)
+	}
+}
@@ -0,0 +1,94 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// user agent pages are fetched with unless another one is given, a current desktop Chrome
+const DefaultUserAgent = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/137.0.0.0 Safari/537.36"
+
+// Fetcher retrieves the content of a page or a script
+type Fetcher interface {
+	Fetch(ctx context.Context, url string) (string, error)
+}
+
+// HTTPError is returned when a page is answered with a non-2xx status
+type HTTPError struct {
+	URL        string
+	StatusCode int
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("fetching %s: unexpected status %d", e.URL, e.StatusCode)
+}
+
+// shared by every fetcher that was not given a client, so its connections are reused across scrapes
+var defaultHTTPClient = &http.Client{
+	Timeout: time.Second * 10,
+	Transport: &http.Transport{ // Force HTTP2
+		ForceAttemptHTTP2: true,
+	},
+}
+
+// HTTPFetcher is the default Fetcher, fetching pages with a plain http client
+type HTTPFetcher struct {
+	Client    *http.Client
+	UserAgent string
+}
+
+// initiates a new http fetcher, a nil client is replaced by a shared one with a 10 seconds timeout
+func NewHTTPFetcher(client *http.Client, userAgent string) *HTTPFetcher {
+	if client == nil {
+		client = defaultHTTPClient
+	}
+	if userAgent == "" {
+		userAgent = DefaultUserAgent
+	}
+
+	return &HTTPFetcher{Client: client, UserAgent: userAgent}
+}
+
+func (f *HTTPFetcher) Fetch(ctx context.Context, url string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", f.UserAgent)
+
+	resp, err := f.Client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", &HTTPError{URL: url, StatusCode: resp.StatusCode}
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	return string(body), err
+}
+
+// Options configures where and how the operations are scraped from
+type Options struct {
+	Fetcher   Fetcher         // used to fetch every page, an HTTPFetcher built from Client and UserAgent when nil
+	Client    *http.Client    // http client of the default fetcher
+	UserAgent string          // user agent of the default fetcher
+	BaseURL   string          // url of the home page, defaults to https://x.com
+	Overrides map[string]bool // feature overrides, see ResolveFeatures
+}
+
+// fills in the defaults of every option that was not set
+func (o Options) withDefaults() Options {
+	if o.Fetcher == nil {
+		o.Fetcher = NewHTTPFetcher(o.Client, o.UserAgent)
+	}
+	if o.BaseURL == "" {
+		o.BaseURL = baseURL
+	}
+	return o
+}
@@ -0,0 +1,32 @@
+package operations
+
+// parseMetadata extracts featureSwitches and fieldToggles from a parsed metadata object.
+func parseMetadata(meta any) metadataRaw {
+	var m metadataRaw
+
+	object, ok := meta.(map[string]any)
+	if !ok {
+		return m
+	}
+
+	m.FeatureSwitches = stringList(object["featureSwitches"])
+	m.FieldToggles = stringList(object["fieldToggles"])
+
+	return m
+}
+
+// stringList returns the string items of a parsed array, skipping anything else.
+func stringList(value any) []string {
+	items, ok := value.([]any)
+	if !ok {
+		return nil
+	}
+
+	var res []string
+	for _, item := range items {
+		if s, ok := item.(string); ok && s != "" {
+			res = append(res, s)
+		}
+	}
+	return res
+}
@@ -0,0 +1,152 @@
+package operations
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ParseScript parses the operations defined in a script, taken to be main.js like GetOperations does.
+func ParseScript(r io.Reader) ([]Operation, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return withSource(parseOperations(content), mainSource), nil
+}
+
+/*
+ParseDir parses the operations of every .js file in a directory of saved chunks.
+Each operation's Source is the name of the file it was found in, main.* files take precedence.
+*/
+func ParseDir(dir string) ([]Operation, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".js") {
+			names = append(names, entry.Name())
+		}
+	}
+	sortScripts(names)
+
+	var lists [][]Operation
+	for _, name := range names {
+		content, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		lists = append(lists, withSource(parseOperations(content), name))
+	}
+
+	return mergeOperations(lists...), nil
+}
+
+// the subset of the HAR format needed to find the scripts of a capture
+type harFile struct {
+	Log struct {
+		Entries []struct {
+			Request struct {
+				URL string `json:"url"`
+			} `json:"request"`
+			Response struct {
+				Content struct {
+					MimeType string `json:"mimeType"`
+					Text     string `json:"text"`
+					Encoding string `json:"encoding"`
+				} `json:"content"`
+			} `json:"response"`
+		} `json:"entries"`
+	} `json:"log"`
+}
+
+/*
+ParseHAR parses the operations of every JavaScript response in a HAR capture. When the capture
+also holds the x.com home page, the feature defaults are resolved from it like GetOperations does.
+*/
+func ParseHAR(r io.Reader) ([]Operation, error) {
+	var har harFile
+	if err := json.NewDecoder(r).Decode(&har); err != nil {
+		return nil, err
+	}
+
+	scripts := map[string][]byte{}
+	var defaults map[string]bool
+	for _, entry := range har.Log.Entries {
+		content := entry.Response.Content
+
+		text := []byte(content.Text)
+		if content.Encoding == "base64" {
+			decoded, err := base64.StdEncoding.DecodeString(content.Text)
+			if err != nil {
+				return nil, err
+			}
+			text = decoded
+		}
+
+		name := scriptName(entry.Request.URL)
+		switch {
+		case strings.Contains(content.MimeType, "javascript") || strings.HasSuffix(name, ".js"):
+			scripts[name] = text
+		case strings.Contains(content.MimeType, "html") && defaults == nil:
+			// not every html response is the home page, those simply have no initial state
+			defaults = parseFeatureDefaults(string(text))
+		}
+	}
+
+	names := make([]string, 0, len(scripts))
+	for name := range scripts {
+		names = append(names, name)
+	}
+	sortScripts(names)
+
+	var lists [][]Operation
+	for _, name := range names {
+		lists = append(lists, withSource(parseOperations(scripts[name]), name))
+	}
+
+	ops := mergeOperations(lists...)
+	if defaults != nil {
+		for i := range ops {
+			ops[i].Features = ResolveFeatures(ops[i].FeatureSwitches, defaults, nil)
+		}
+	}
+	return ops, nil
+}
+
+// returns the file name of a script's url
+func scriptName(rawUrl string) string {
+	parsed, err := url.Parse(rawUrl)
+	if err != nil {
+		return rawUrl
+	}
+	return path.Base(parsed.Path)
+}
+
+// sorts script names alphabetically, main.* scripts first
+func sortScripts(names []string) {
+	sort.Slice(names, func(i, j int) bool {
+		iMain := strings.HasPrefix(names[i], "main.")
+		jMain := strings.HasPrefix(names[j], "main.")
+		if iMain != jMain {
+			return iMain
+		}
+		return names[i] < names[j]
+	})
+}
+
+func withSource(ops []Operation, source string) []Operation {
+	for i := range ops {
+		ops[i].Source = source
+	}
+	return ops
+}
@@ -0,0 +1,99 @@
+package operations
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files")
+
+// compares operations with the JSON of a golden file
+func checkGolden(t *testing.T, golden string, ops []Operation) {
+	t.Helper()
+
+	got, err := json.MarshalIndent(ops, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = append(got, '\n')
+
+	if *update {
+		if err := os.WriteFile(golden, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("operations differ from %s, rerun with -update to accept them:\n%s", golden, got)
+	}
+}
+
+func TestParseScriptGolden(t *testing.T) {
+	f, err := os.Open("testdata/offline/main.js")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	ops, err := ParseScript(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "testdata/offline/main.golden.json", ops)
+}
+
+func TestParseDirGolden(t *testing.T) {
+	ops, err := ParseDir("testdata/offline/chunks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "testdata/offline/chunks.golden.json", ops)
+}
+
+func TestParseHARGolden(t *testing.T) {
+	f, err := os.Open("testdata/offline/capture.har")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	ops, err := ParseHAR(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "testdata/offline/har.golden.json", ops)
+}
+
+func FuzzParseScript(f *testing.F) {
+	main, err := os.ReadFile("testdata/offline/main.js")
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add(main)
+	f.Add([]byte(`{queryId:"x",b: ,c:1}`))
+	f.Add([]byte(`{queryId:"x",operationName:"y",operationType:"query",metadata:{featureSwitches:[//0`))
+	f.Add([]byte(`1:e=>{e.exports={queryId:"x",operationName:"y",operationType:"query"}},2:(e,t,n)=>{f(n(1),{a:` + "`${{b:1}}`" + `})}`))
+
+	f.Fuzz(func(t *testing.T, script []byte) {
+		ops, err := ParseScript(bytes.NewReader(script))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, op := range ops {
+			if op.QueryID == "" || op.OperationName == "" || op.OperationType == "" || op.Source != mainSource {
+				t.Fatalf("incomplete operation %+v", op)
+			}
+			if !strings.Contains(string(script), op.QueryID) {
+				t.Fatalf("query id %q is not in the script", op.QueryID)
+			}
+		}
+	})
+}
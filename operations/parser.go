@@ -0,0 +1,127 @@
+package operations
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/nitayStain/x-aio/internal/jsparse"
+)
+
+// matches the start of an operation's object literal, the rest of it is read by jsparse
+var operationRegex = regexp.MustCompile(`\{queryId:["']`)
+
+// GetOperations retrieves and parses all GraphQL operations from x.com's main script.
+func GetOperations() ([]Operation, error) {
+	return GetOperationsWithOptions(context.Background(), Options{})
+}
+
+/*
+GetOperationsWithOverrides works like GetOperations, resolving each operation's features
+from the home page's defaults while preferring the values given in overrides.
+*/
+func GetOperationsWithOverrides(overrides map[string]bool) ([]Operation, error) {
+	return GetOperationsWithOptions(context.Background(), Options{Overrides: overrides})
+}
+
+// GetOperationsWithOptions works like GetOperations, fetching the pages as configured by opts.
+func GetOperationsWithOptions(ctx context.Context, opts Options) ([]Operation, error) {
+	snapshot, err := GetSnapshot(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshot.Operations, nil
+}
+
+/*
+GetSnapshot scrapes the operations along with everything else main.js exposes:
+the web client's bearer token and the REST endpoints it calls.
+*/
+func GetSnapshot(ctx context.Context, opts Options) (*Snapshot, error) {
+	s := newScraper(ctx, opts)
+
+	mainPageContent, err := s.getMainPage()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.scrapeSnapshot(mainPageContent, opts.Overrides)
+}
+
+// GetSnapshotFromHomePage works like GetSnapshot, with a home page that was already fetched.
+func GetSnapshotFromHomePage(ctx context.Context, opts Options, homePage string) (*Snapshot, error) {
+	return newScraper(ctx, opts).scrapeSnapshot(homePage, opts.Overrides)
+}
+
+// scrapeSnapshot fetches the main script referenced by the home page and parses its operations.
+func (s *scraper) scrapeSnapshot(mainPageContent string, overrides map[string]bool) (*Snapshot, error) {
+	mainScriptUrl, mainScriptContent, err := s.getMainScript(mainPageContent)
+	if err != nil {
+		return nil, err
+	}
+
+	defaults := parseFeatureDefaults(mainPageContent)
+
+	ops := parseOperations([]byte(mainScriptContent))
+	for i := range ops {
+		ops[i].Features = ResolveFeatures(ops[i].FeatureSwitches, defaults, overrides)
+		ops[i].Source = mainSource
+	}
+
+	return &Snapshot{
+		MainScriptURL:  mainScriptUrl,
+		MainScriptHash: hashContent(mainScriptContent),
+		FetchedAt:      time.Now(),
+		Operations:     ops,
+		BearerToken:    parseBearerToken(mainScriptContent),
+		RESTEndpoints:  parseRESTEndpoints(mainScriptContent),
+	}, nil
+}
+
+// parseOperations extracts every GraphQL operation defined in a script's content.
+func parseOperations(content []byte) []Operation {
+	var ops []Operation
+	var starts []int
+
+	for _, m := range operationRegex.FindAllIndex(content, -1) {
+		value, _, err := jsparse.ParseValue(content[m[0]:])
+		if err != nil {
+			continue
+		}
+
+		op, ok := operationFromObject(value)
+		if !ok {
+			continue
+		}
+		ops = append(ops, op)
+		starts = append(starts, m[0])
+	}
+
+	inferVariables(content, ops, starts)
+	return ops
+}
+
+// operationFromObject reads an operation out of a parsed {queryId:...,metadata:{...}} object literal.
+func operationFromObject(value any) (Operation, bool) {
+	object, ok := value.(map[string]any)
+	if !ok {
+		return Operation{}, false
+	}
+
+	queryID, _ := object["queryId"].(string)
+	name, _ := object["operationName"].(string)
+	opType, _ := object["operationType"].(string)
+	if queryID == "" || name == "" || opType == "" {
+		return Operation{}, false
+	}
+
+	meta := parseMetadata(object["metadata"])
+	return Operation{
+		QueryID:         queryID,
+		OperationName:   name,
+		OperationType:   opType,
+		FeatureSwitches: meta.FeatureSwitches,
+		FieldToggles:    meta.FieldToggles,
+	}, true
+}
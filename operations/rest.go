@@ -0,0 +1,38 @@
+package operations
+
+import (
+	"regexp"
+	"sort"
+)
+
+var (
+	// the public bearer token of the web client, it always starts with a run of A's
+	bearerTokenRegex = regexp.MustCompile(`["'](AAAAAAAAAAAAAAAAAAAAA[A-Za-z0-9%]{30,})["']`)
+	// REST paths, either bare ("/1.1/...") or prefixed with the api host
+	restEndpointRegex = regexp.MustCompile(`["'](?:https://(?:api\.)?(?:x|twitter)\.com)?(/(?:1\.1|2)/[A-Za-z0-9_.\-/{}:]+)["']`)
+)
+
+// parseBearerToken extracts the web client's bearer token from a script's content.
+func parseBearerToken(content string) string {
+	match := bearerTokenRegex.FindStringSubmatch(content)
+	if len(match) < 2 {
+		return ""
+	}
+	return match[1]
+}
+
+// parseRESTEndpoints extracts the sorted, deduplicated REST endpoint paths referenced by a script.
+func parseRESTEndpoints(content string) []string {
+	seen := map[string]bool{}
+
+	var endpoints []string
+	for _, match := range restEndpointRegex.FindAllStringSubmatch(content, -1) {
+		if !seen[match[1]] {
+			seen[match[1]] = true
+			endpoints = append(endpoints, match[1])
+		}
+	}
+
+	sort.Strings(endpoints)
+	return endpoints
+}
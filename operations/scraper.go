@@ -0,0 +1,77 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+)
+
+const baseURL = "https://x.com"
+
+// scraper fetches the pages the operations are parsed from
+type scraper struct {
+	ctx     context.Context
+	fetcher Fetcher
+	baseURL string
+}
+
+func newScraper(ctx context.Context, opts Options) *scraper {
+	opts = opts.withDefaults()
+	return &scraper{ctx: ctx, fetcher: opts.Fetcher, baseURL: opts.BaseURL}
+}
+
+// This function simply retrieves the content of the main X page.
+func (s *scraper) getMainPage() (string, error) {
+	return s.fetcher.Fetch(s.ctx, s.baseURL)
+}
+
+/*
+This function uses regex to extract the main.js script from the x.com homepage.
+The main.js contains the GraphQL operations that X uses in their GraphQL api
+*/
+func getMainScriptHref(html string) (string, error) {
+	re := regexp.MustCompile(`<link[^>]+rel=["']preload["'][^>]+as=["']script["'][^>]+href=["']([^"']*main\.[^"']*\.js)["']`)
+	match := re.FindStringSubmatch(html)
+	if len(match) < 2 {
+		return "", fmt.Errorf("main script not found")
+	}
+	return match[1], nil
+}
+
+// This function returns the absolute url of the main.js script, resolved against the home page
+func (s *scraper) getMainScriptUrl(html string) (string, error) {
+	href, err := getMainScriptHref(html)
+	if err != nil {
+		return "", err
+	}
+	return resolveURL(s.baseURL, href)
+}
+
+// This function retrieves the url and the content of the main.js script
+func (s *scraper) getMainScript(html string) (string, string, error) {
+	mainScriptUrl, err := s.getMainScriptUrl(html)
+	if err != nil {
+		return "", "", err
+	}
+
+	content, err := s.fetcher.Fetch(s.ctx, mainScriptUrl)
+	if err != nil {
+		return "", "", err
+	}
+
+	return mainScriptUrl, content, nil
+}
+
+// resolves a possibly relative reference against a base url
+func resolveURL(base, ref string) (string, error) {
+	baseUrl, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+	refUrl, err := url.Parse(ref)
+	if err != nil {
+		return "", err
+	}
+	return baseUrl.ResolveReference(refUrl).String(), nil
+}
@@ -0,0 +1,4 @@
+(self.webpackChunk_twitter_responsive_web=self.webpackChunk_twitter_responsive_web||[]).push([["bundle.Profile"],{
+34567:e=>{e.exports={queryId:"1VOOyvKkiI3FMmkeDNxM9A",operationName:"UserByScreenName",operationType:"query",metadata:{featureSwitches:[],fieldToggles:[]}}},
+45678:e=>{e.exports={queryId:"q9Yg8VgqU9dFxdIGqGqHkQ",operationName:"UserTweets",operationType:"query",metadata:{featureSwitches:["responsive_web_graphql_timeline_navigation_enabled"],fieldToggles:["withArticlePlainText"]}}}
+}]);
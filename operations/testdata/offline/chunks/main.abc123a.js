@@ -0,0 +1,7 @@
+(self.webpackChunk_twitter_responsive_web=self.webpackChunk_twitter_responsive_web||[]).push([["main"],{
+12345:e=>{e.exports={queryId:"1VOOyvKkiI3FMmkeDNxM9A",operationName:"UserByScreenName",operationType:"query",metadata:{featureSwitches:["hidden_profile_subscriptions_enabled","verified_phone_label_enabled"],fieldToggles:["withAuxiliaryUserLabels"]}}},
+23456:e=>{e.exports={queryId:'SoVnbfCycZ7fERGCwpZkYA',operationName:"CreateTweet",operationType:"mutation",metadata:{featureSwitches:["verified_phone_label_enabled"],fieldToggles:[]}}},
+678:(e,t,n)=>{var r=n(12345),s="{queryId:\"not an operation\"",p=/\{queryId:"regex"\}/g,q=`{queryId:"${s}"}`;
+function u(e){return fetchQuery(r,{screen_name:e,withSafetyModeUserFields:!0})}
+function v(e){return commitMutation(n(23456),{tweet_text:e,dark_request:!1,media:{media_entities:[],possibly_sensitive:!1}})}}
+}]);
@@ -0,0 +1,22 @@
+package operations
+
+/*
+This type represents the GraphQL operation.
+*/
+type Operation struct {
+	QueryID         string          `json:"queryId"`
+	OperationName   string          `json:"operationName"`
+	OperationType   string          `json:"operationType"`
+	FeatureSwitches []string        `json:"featureSwitches"`
+	FieldToggles    []string        `json:"fieldToggles"`
+	Features        map[string]bool `json:"features"` // resolved value of every feature switch
+	Source          string          `json:"source"`   // name of the script the operation was found in
+
+	Variables        []string       `json:"variables"`        // variable names inferred from the operation's call sites
+	VariableDefaults map[string]any `json:"variableDefaults"` // literal values passed for some of the variables
+}
+
+type metadataRaw struct {
+	FeatureSwitches []string
+	FieldToggles    []string
+}
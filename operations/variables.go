@@ -0,0 +1,196 @@
+package operations
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/nitayStain/x-aio/internal/jsparse"
+)
+
+// how far after a module reference its call sites are searched for
+const variablesWindow = 4096
+
+var (
+	// the webpack module wrapping an operation: 12345:e=>{e.exports={queryId:...
+	moduleHeaderRegex = regexp.MustCompile(`(\d+):(?:function\s*\([^)]*\)|\(?[\w$,]*\)?\s*=>)\s*\{\s*[\w$]+\.exports\s*=\s*$`)
+	// the start of any webpack module, its third parameter is the require function: 678:(e,t,n)=>{
+	moduleStartRegex = regexp.MustCompile(`\d+:\s*(?:function\s*\(([^)]*)\)|\(([^)]*)\)\s*=>|[\w$]+\s*=>)\s*\{`)
+	identRegex       = regexp.MustCompile(`^[\w$]+$`)
+	// an object passed right after an inline require: fetchQuery(n(12345),{...})
+	inlineArgRegex = regexp.MustCompile(`^\s*,\s*\{`)
+)
+
+// a place a module is required from
+type moduleRef struct {
+	alias string // variable the module is assigned to, empty when it is used inline
+	end   int    // offset right after the require call
+}
+
+/*
+inferVariables fills in the variables of each operation by looking at where its module is used.
+The object literal passed next to the operation, e.g. fetchQuery(r,{screen_name:e,count:20}),
+is taken as its variables, and literal values are kept as defaults.
+*/
+func inferVariables(content []byte, ops []Operation, starts []int) {
+	ids := make([]string, len(ops))
+	found := false
+	for i, start := range starts {
+		ids[i] = moduleID(content, start)
+		found = found || ids[i] != ""
+	}
+	if !found {
+		return
+	}
+
+	refs := indexModuleRefs(content)
+	aliasPatterns := map[string]*regexp.Regexp{}
+	for i := range ops {
+		if ids[i] == "" {
+			continue
+		}
+		ops[i].Variables, ops[i].VariableDefaults = collectVariables(content, refs[ids[i]], aliasPatterns)
+	}
+}
+
+// returns the id of the webpack module an operation literal is exported from
+func moduleID(content []byte, start int) string {
+	from := max(0, start-128)
+	match := moduleHeaderRegex.FindSubmatch(content[from:start])
+	if match == nil {
+		return ""
+	}
+	return string(match[1])
+}
+
+/*
+indexModuleRefs indexes the require calls of every module by required module id. Only calls of the
+require function a module receives count, so unrelated calls like setTimeout(500) are left out.
+*/
+func indexModuleRefs(content []byte) map[string][]moduleRef {
+	refs := map[string][]moduleRef{}
+	refPatterns := map[string]*regexp.Regexp{}
+
+	headers := moduleStartRegex.FindAllSubmatchIndex(content, -1)
+	for i, header := range headers {
+		require := requireParam(content, header)
+		if require == "" {
+			continue
+		}
+
+		// a module's body runs until the next module starts
+		end := len(content)
+		if i+1 < len(headers) {
+			end = headers[i+1][0]
+		}
+		body := content[header[1]:end]
+
+		pattern, ok := refPatterns[require]
+		if !ok {
+			// the module being required, optionally into an alias: r=n(12345)
+			pattern = regexp.MustCompile(`(?:([\w$]+)\s*=\s*|[^\w$.])` + regexp.QuoteMeta(require) + `\((\d+)\)`)
+			refPatterns[require] = pattern
+		}
+
+		for _, m := range pattern.FindAllSubmatchIndex(body, -1) {
+			ref := moduleRef{end: header[1] + m[1]}
+			if m[2] >= 0 {
+				ref.alias = string(body[m[2]:m[3]])
+			}
+			id := string(body[m[4]:m[5]])
+			refs[id] = append(refs[id], ref)
+		}
+	}
+	return refs
+}
+
+// returns the name of the require function a module receives, empty when it takes none
+func requireParam(content []byte, header []int) string {
+	var params []byte
+	switch {
+	case header[2] >= 0:
+		params = content[header[2]:header[3]]
+	case header[4] >= 0:
+		params = content[header[4]:header[5]]
+	}
+
+	names := strings.Split(string(params), ",")
+	if len(names) < 3 {
+		return ""
+	}
+
+	// destructured or default parameters are not a plain require function
+	require := strings.TrimSpace(names[2])
+	if !identRegex.MatchString(require) {
+		return ""
+	}
+	return require
+}
+
+// collects the keys, and literal values, of the objects passed along with a module's references
+func collectVariables(content []byte, refs []moduleRef, aliasPatterns map[string]*regexp.Regexp) ([]string, map[string]any) {
+	keys := map[string]bool{}
+	defaults := map[string]any{}
+
+	for _, ref := range refs {
+		end := min(ref.end+variablesWindow, len(content))
+		window := content[ref.end:end]
+
+		argRegex := inlineArgRegex
+		if ref.alias != "" {
+			argRegex = aliasPattern(aliasPatterns, ref.alias)
+		}
+
+		for _, m := range argRegex.FindAllIndex(window, -1) {
+			value, _, err := jsparse.ParseValue(window[m[1]-1:])
+			if err != nil {
+				continue
+			}
+			object, ok := value.(map[string]any)
+			if !ok {
+				continue
+			}
+
+			for key, v := range object {
+				keys[key] = true
+				if _, seen := defaults[key]; !seen && isLiteral(v) {
+					defaults[key] = v
+				}
+			}
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	variables := make([]string, 0, len(keys))
+	for key := range keys {
+		variables = append(variables, key)
+	}
+	sort.Strings(variables)
+
+	if len(defaults) == 0 {
+		defaults = nil
+	}
+	return variables, defaults
+}
+
+// returns the pattern of an object passed after an alias, compiling it on first use: fetchQuery(r,{...})
+func aliasPattern(patterns map[string]*regexp.Regexp, alias string) *regexp.Regexp {
+	pattern, ok := patterns[alias]
+	if !ok {
+		pattern = regexp.MustCompile(`[(,]\s*` + regexp.QuoteMeta(alias) + `\s*,\s*\{`)
+		patterns[alias] = pattern
+	}
+	return pattern
+}
+
+// reports whether a parsed value is a plain literal, as opposed to an identifier or expression
+func isLiteral(value any) bool {
+	switch value.(type) {
+	case nil, bool, float64, string:
+		return true
+	}
+	return false
+}
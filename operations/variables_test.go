@@ -0,0 +1,39 @@
+package operations
+
+import (
+	"reflect"
+	"testing"
+)
+
+const variablesBundle = `(self.webpackChunk=self.webpackChunk||[]).push([[1],{` +
+	`12345:e=>{e.exports={queryId:"q1",operationName:"UserByScreenName",operationType:"query",metadata:{featureSwitches:[],fieldToggles:[]}}},` +
+	`678:(e,t,n)=>{var r=n(12345);function u(e){return fetchQuery(r,{screen_name:e,withSafetyModeUserFields:!0})}` +
+	`function v(){return fetchQuery(n(12345),{count:20})}},` +
+	`910:function(e,t,r){f(setTimeout(12345),{ignored:1});f(o.t(12345),{ignored:2});var x=s(12345);g(x,{ignored:3})}` +
+	`}]);`
+
+func TestParseOperationsVariables(t *testing.T) {
+	ops := parseOperations([]byte(variablesBundle))
+	if len(ops) != 1 {
+		t.Fatalf("got %d operations, want 1", len(ops))
+	}
+
+	wantVariables := []string{"count", "screen_name", "withSafetyModeUserFields"}
+	if !reflect.DeepEqual(ops[0].Variables, wantVariables) {
+		t.Errorf("variables = %v, want %v", ops[0].Variables, wantVariables)
+	}
+
+	wantDefaults := map[string]any{"count": 20.0, "withSafetyModeUserFields": true}
+	if !reflect.DeepEqual(ops[0].VariableDefaults, wantDefaults) {
+		t.Errorf("variable defaults = %v, want %v", ops[0].VariableDefaults, wantDefaults)
+	}
+}
+
+// a third parameter that is not an identifier must not end up in a require pattern
+func TestParseOperationsInvalidRequireParam(t *testing.T) {
+	bundle := "1:e=>{e.exports=" + `{queryId:"q1",operationName:"A",operationType:"query"}` + "},2:(e,t,\xba)=>{f(\xba(1),{a:1})},3:(e,t,{n})=>{f(n(1),{b:1})}"
+	ops := parseOperations([]byte(bundle))
+	if len(ops) != 1 || ops[0].Variables != nil {
+		t.Fatalf("got %+v, want one operation without variables", ops)
+	}
+}
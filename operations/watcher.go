@@ -0,0 +1,193 @@
+package operations
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+const defaultWatchInterval = 5 * time.Minute
+
+type EventType string
+
+const (
+	DeploymentEvent             EventType = "deployment"               // a new main script was deployed, or the watcher started
+	OperationAddedEvent         EventType = "operation_added"          // an operation appeared in a new deployment
+	OperationRemovedEvent       EventType = "operation_removed"        // an operation disappeared in a new deployment
+	OperationChangedEvent       EventType = "operation_changed"        // an operation's query id, feature switches or field toggles changed
+	VerificationKeyRotatedEvent EventType = "verification_key_rotated" // the twitter-site-verification key changed
+)
+
+// Event is emitted by a Watcher, only the fields related to its type are set
+type Event struct {
+	Type      EventType
+	Snapshot  *Snapshot        // DeploymentEvent
+	Operation *Operation       // OperationAddedEvent, OperationRemovedEvent
+	Change    *OperationChange // OperationChangedEvent
+
+	VerificationKey         string // VerificationKeyRotatedEvent
+	PreviousVerificationKey string // VerificationKeyRotatedEvent
+}
+
+// WatcherOptions configures a Watcher
+type WatcherOptions struct {
+	Options
+	Interval time.Duration // time between polls, defaults to 5 minutes
+	OnEvent  func(Event)   // receives the events instead of the Events channel when set
+	OnError  func(error)   // receives the errors of failed polls, which are skipped otherwise
+}
+
+/*
+Watcher polls the x.com home page and reports new client deployments. The main script is only
+scraped again when its url changes, so most polls cost a single request.
+*/
+type Watcher struct {
+	opts   WatcherOptions
+	events chan Event
+
+	mu              sync.RWMutex
+	snapshot        *Snapshot
+	verificationKey string
+}
+
+// initiates a new watcher, it starts polling once Run is called
+func NewWatcher(opts WatcherOptions) *Watcher {
+	if opts.Interval <= 0 {
+		opts.Interval = defaultWatchInterval
+	}
+	opts.Options = opts.Options.withDefaults()
+	return &Watcher{opts: opts, events: make(chan Event, 16)}
+}
+
+// returns the channel events are delivered on, it is closed once Run returns
+func (w *Watcher) Events() <-chan Event {
+	return w.events
+}
+
+// returns the snapshot of the latest deployment seen
+func (w *Watcher) Snapshot() *Snapshot {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.snapshot
+}
+
+// returns the latest twitter-site-verification key seen
+func (w *Watcher) VerificationKey() string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.verificationKey
+}
+
+/*
+Run polls until the context is cancelled. The first poll emits a DeploymentEvent with the current
+snapshot, and its failure is returned, later failures are reported to OnError and retried on the next tick.
+*/
+func (w *Watcher) Run(ctx context.Context) error {
+	defer close(w.events)
+
+	if err := w.poll(ctx); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(w.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := w.poll(ctx); err != nil && w.opts.OnError != nil && ctx.Err() == nil {
+				w.opts.OnError(err)
+			}
+		}
+	}
+}
+
+func (w *Watcher) poll(ctx context.Context) error {
+	s := newScraper(ctx, w.opts.Options)
+
+	mainPageContent, err := s.getMainPage()
+	if err != nil {
+		return err
+	}
+
+	mainScriptUrl, err := s.getMainScriptUrl(mainPageContent)
+	if err != nil {
+		return err
+	}
+
+	previous := w.Snapshot()
+	previousKey := w.VerificationKey()
+
+	if key := parseVerificationKey(mainPageContent); key != "" && key != previousKey {
+		w.mu.Lock()
+		w.verificationKey = key
+		w.mu.Unlock()
+
+		if previousKey != "" {
+			w.emit(ctx, Event{
+				Type:                    VerificationKeyRotatedEvent,
+				VerificationKey:         key,
+				PreviousVerificationKey: previousKey,
+			})
+		}
+	}
+
+	if previous != nil && previous.MainScriptURL == mainScriptUrl {
+		return nil
+	}
+
+	snapshot, err := s.scrapeSnapshot(mainPageContent, w.opts.Overrides)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	w.snapshot = snapshot
+	w.mu.Unlock()
+
+	w.emit(ctx, Event{Type: DeploymentEvent, Snapshot: snapshot})
+	if previous == nil {
+		return nil
+	}
+
+	changes := Diff(previous.Operations, snapshot.Operations)
+	for i := range changes.Added {
+		w.emit(ctx, Event{Type: OperationAddedEvent, Operation: &changes.Added[i]})
+	}
+	for i := range changes.Removed {
+		w.emit(ctx, Event{Type: OperationRemovedEvent, Operation: &changes.Removed[i]})
+	}
+	for i := range changes.Changed {
+		w.emit(ctx, Event{Type: OperationChangedEvent, Change: &changes.Changed[i]})
+	}
+	return nil
+}
+
+// delivers an event to the callback, or to the channel unless the context is cancelled first
+func (w *Watcher) emit(ctx context.Context, event Event) {
+	if w.opts.OnEvent != nil {
+		w.opts.OnEvent(event)
+		return
+	}
+
+	select {
+	case w.events <- event:
+	case <-ctx.Done():
+	}
+}
+
+// parseVerificationKey extracts the twitter-site-verification key the transaction ids are derived from, whatever the order of its attributes.
+func parseVerificationKey(html string) string {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return ""
+	}
+
+	content, _ := doc.Find(`meta[name="twitter-site-verification"]`).First().Attr("content")
+	return content
+}
@@ -0,0 +1,166 @@
+package operations
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+// a mapFetcher whose pages can be replaced while a watcher polls it
+type syncFetcher struct {
+	mu    sync.Mutex
+	pages mapFetcher
+}
+
+func (f *syncFetcher) Fetch(ctx context.Context, url string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.pages.Fetch(ctx, url)
+}
+
+func (f *syncFetcher) set(pages mapFetcher) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.pages = pages
+}
+
+// serves a home page with the given verification key, pointing to a main script holding the given operations
+func watcherPages(key, mainScript string, ops ...string) mapFetcher {
+	// content comes before name, as the attributes are not always written in the same order
+	homePage := `<html><head><meta content="` + key + `" name="twitter-site-verification">` +
+		`<link rel="preload" as="script" href="` + testScriptsURL + mainScript + `"></head></html>`
+
+	script := ""
+	for _, op := range ops {
+		script += `e.exports=` + op + `;`
+	}
+	return mapFetcher{"https://x.com": homePage, testScriptsURL + mainScript: script}
+}
+
+func TestWatcherPoll(t *testing.T) {
+	fetcher := &syncFetcher{}
+	fetcher.set(watcherPages("key1", "main.a.js", testOperation("q1", "Kept"), testOperation("q2", "Removed"), testOperation("q3", "Changed")))
+
+	var events []Event
+	w := NewWatcher(WatcherOptions{
+		Options: Options{Fetcher: fetcher},
+		OnEvent: func(event Event) { events = append(events, event) },
+	})
+	poll := func() []Event {
+		t.Helper()
+
+		events = nil
+		if err := w.poll(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		return events
+	}
+
+	got := poll()
+	if len(got) != 1 || got[0].Type != DeploymentEvent || got[0].Snapshot.MainScriptURL != testScriptsURL+"main.a.js" {
+		t.Fatalf("first poll: got %+v, want a single deployment", got)
+	}
+	if w.VerificationKey() != "key1" || w.Snapshot() != got[0].Snapshot {
+		t.Fatalf("watcher state not updated: key %q", w.VerificationKey())
+	}
+
+	if got := poll(); len(got) != 0 {
+		t.Fatalf("unchanged poll: got %+v, want no events", got)
+	}
+
+	fetcher.set(watcherPages("key2", "main.a.js", testOperation("q1", "Kept")))
+	got = poll()
+	if len(got) != 1 || got[0].Type != VerificationKeyRotatedEvent ||
+		got[0].VerificationKey != "key2" || got[0].PreviousVerificationKey != "key1" {
+		t.Fatalf("key rotation: got %+v", got)
+	}
+	if len(w.Snapshot().Operations) != 3 {
+		t.Fatal("the main script was scraped again although its url did not change")
+	}
+
+	fetcher.set(watcherPages("key2", "main.b.js", testOperation("q1", "Kept"), testOperation("q4", "Changed"), testOperation("q5", "Added")))
+	got = poll()
+
+	var types []EventType
+	for _, event := range got {
+		types = append(types, event.Type)
+	}
+	want := []EventType{DeploymentEvent, OperationAddedEvent, OperationRemovedEvent, OperationChangedEvent}
+	if len(types) != len(want) {
+		t.Fatalf("deployment: got events %v, want %v", types, want)
+	}
+	for i := range want {
+		if types[i] != want[i] {
+			t.Fatalf("deployment: got events %v, want %v", types, want)
+		}
+	}
+	if got[1].Operation.OperationName != "Added" || got[2].Operation.OperationName != "Removed" {
+		t.Fatalf("got added %q and removed %q", got[1].Operation.OperationName, got[2].Operation.OperationName)
+	}
+	if change := got[3].Change; change.OperationName != "Changed" || change.OldQueryID != "q3" || change.NewQueryID != "q4" {
+		t.Fatalf("got change %+v", change)
+	}
+}
+
+func TestWatcherRun(t *testing.T) {
+	fetcher := &syncFetcher{}
+	fetcher.set(watcherPages("key1", "main.a.js", testOperation("q1", "Kept")))
+
+	errs := make(chan error, 1)
+	w := NewWatcher(WatcherOptions{
+		Options:  Options{Fetcher: fetcher},
+		Interval: time.Millisecond,
+		OnError: func(err error) {
+			select {
+			case errs <- err:
+			default:
+			}
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- w.Run(ctx) }()
+
+	if event := <-w.Events(); event.Type != DeploymentEvent {
+		t.Fatalf("got %+v, want a deployment", event)
+	}
+
+	// later failures are reported and do not stop the watcher
+	fetcher.set(mapFetcher{})
+	var httpErr *HTTPError
+	if err := <-errs; !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("got %v, want a 404", err)
+	}
+
+	fetcher.set(watcherPages("key2", "main.a.js", testOperation("q1", "Kept")))
+	if event := <-w.Events(); event.Type != VerificationKeyRotatedEvent {
+		t.Fatalf("got %+v, want a key rotation", event)
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("got %v, want context.Canceled", err)
+	}
+	if _, ok := <-w.Events(); ok {
+		t.Fatal("events channel not closed")
+	}
+}
+
+func TestWatcherFirstPollError(t *testing.T) {
+	w := NewWatcher(WatcherOptions{Options: Options{Fetcher: mapFetcher{}}})
+
+	var httpErr *HTTPError
+	if err := w.Run(context.Background()); !errors.As(err, &httpErr) || httpErr.URL != "https://x.com" {
+		t.Fatalf("got %v, want the home page's HTTPError", err)
+	}
+	if _, ok := <-w.Events(); ok {
+		t.Fatal("events channel not closed")
+	}
+	if w.Snapshot() != nil {
+		t.Fatal("snapshot set after a failed poll")
+	}
+}
@@ -0,0 +1,106 @@
+package xaio
+
+import (
+	"net/http"
+
+	"github.com/nitayStain/x-aio/operations"
+	"github.com/nitayStain/x-aio/tid"
+)
+
+// Option configures a Client while it is being built
+type Option func(*config)
+
+type config struct {
+	userAgent   string
+	headers     map[string]string
+	cookies     map[string]string
+	httpClient  *http.Client
+	transaction *tid.ClientTransaction
+	operations  []operations.Operation
+	bearerToken string
+
+	features     map[string]bool
+	fieldToggles map[string]bool
+}
+
+func defaultConfig() *config {
+	return &config{
+		userAgent: operations.DefaultUserAgent,
+		headers:   map[string]string{},
+		cookies:   map[string]string{},
+
+		features:     map[string]bool{},
+		fieldToggles: map[string]bool{},
+	}
+}
+
+// sets the user agent sent with every request
+func WithUserAgent(userAgent string) Option {
+	return func(c *config) {
+		c.userAgent = userAgent
+	}
+}
+
+// adds headers that are sent with every request
+func WithHeaders(headers map[string]string) Option {
+	return func(c *config) {
+		for k, v := range headers {
+			c.headers[k] = v
+		}
+	}
+}
+
+// adds cookies that are sent with every request (e.g. auth_token, ct0)
+func WithCookies(cookies map[string]string) Option {
+	return func(c *config) {
+		for k, v := range cookies {
+			c.cookies[k] = v
+		}
+	}
+}
+
+// replaces the http client used by the request client
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *config) {
+		c.httpClient = client
+	}
+}
+
+// uses an existing transaction instead of deriving a new one from x.com
+func WithTransaction(transaction *tid.ClientTransaction) Option {
+	return func(c *config) {
+		c.transaction = transaction
+	}
+}
+
+// uses a known list of operations instead of scraping x.com
+func WithOperations(ops []operations.Operation) Option {
+	return func(c *config) {
+		c.operations = ops
+	}
+}
+
+// sets the bearer token sent in the Authorization header, instead of the one scraped from main.js
+func WithBearerToken(token string) Option {
+	return func(c *config) {
+		c.bearerToken = token
+	}
+}
+
+// sets feature switch values sent with GraphQL requests
+func WithFeatures(features map[string]bool) Option {
+	return func(c *config) {
+		for k, v := range features {
+			c.features[k] = v
+		}
+	}
+}
+
+// sets field toggle values sent with GraphQL requests
+func WithFieldToggles(toggles map[string]bool) Option {
+	return func(c *config) {
+		for k, v := range toggles {
+			c.fieldToggles[k] = v
+		}
+	}
+}
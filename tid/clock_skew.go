@@ -0,0 +1,84 @@
+package tid
+
+import (
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// weight of a new sample in the smoothed offset
+const defaultSkewAlpha = 0.2
+
+/*
+ClockSkew keeps a smoothed estimate of how far X's clock is ahead of the local one, measured
+from the Date header of its responses. It is safe for concurrent use.
+*/
+type ClockSkew struct {
+	Alpha float64 // weight of a new sample, between 0 and 1
+
+	mu      sync.Mutex
+	offset  float64 // nanoseconds
+	samples int
+
+	current atomic.Int64 // offset rounded to a duration, read without the lock on every generated id
+}
+
+// initiates a new clock skew estimate with the default smoothing
+func NewClockSkew() *ClockSkew {
+	return &ClockSkew{Alpha: defaultSkewAlpha}
+}
+
+// returns the current estimate, to be added to the local time to get X's time
+func (s *ClockSkew) Offset() time.Duration {
+	if s == nil {
+		return 0
+	}
+
+	return time.Duration(s.current.Load())
+}
+
+// returns how many responses the estimate is based on
+func (s *ClockSkew) Samples() int {
+	if s == nil {
+		return 0
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.samples
+}
+
+// Observe adds a sample: a Date header value and the local time the response was received at.
+func (s *ClockSkew) Observe(date string, local time.Time) {
+	if s == nil {
+		return
+	}
+
+	server, err := http.ParseTime(date)
+	if err != nil {
+		return
+	}
+
+	// the header is truncated to the second, on average the server was half a second further
+	sample := float64(server.Add(500 * time.Millisecond).Sub(local))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.samples == 0 {
+		s.offset = sample
+	} else {
+		s.offset += s.Alpha * (sample - s.offset)
+	}
+	s.samples++
+	s.current.Store(int64(s.offset))
+}
+
+// adds a sample from a response that was just received
+func (s *ClockSkew) ObserveResponse(res *http.Response) {
+	if s == nil || res == nil {
+		return
+	}
+	s.Observe(res.Header.Get("Date"), time.Now())
+}
@@ -42,7 +42,7 @@ func (c *Cubic) GetValue(time float64) float64 {
 
 	for startValue < endValue {
 		mid = (startValue + endValue) / 2.0
-		x := bezier(c.Curves[0], c.Curves[1], mid)
+		x := bezier(c.Curves[0], c.Curves[2], mid)
 		if math.Abs(time-x) < 0.00001 {
 			return bezier(c.Curves[1], c.Curves[3], mid)
 		}
@@ -0,0 +1,60 @@
+package tid
+
+import (
+	"bytes"
+	"errors"
+	"time"
+)
+
+var ErrKeyBytesMismatch = errors.New("transaction id was not generated from these key bytes")
+
+// DecodedTransactionID is the content of an x-client-transaction-id, once un-XORed
+type DecodedTransactionID struct {
+	Version                int // version of the generator that decoded the id
+	RandomByte             byte
+	KeyBytes               []byte
+	Timestamp              uint32    // seconds since the generator's epoch, as embedded in the id
+	Time                   time.Time // wall-clock time the id was generated at
+	Hash                   []byte    // truncated hash of the request and the transaction's state
+	AdditionalRandomNumber byte
+}
+
+// DecodeTransactionID reverses GenerateTransactionID for ids of the default generator version.
+func DecodeTransactionID(id string) (*DecodedTransactionID, error) {
+	generator, err := GetGenerator(DefaultGeneratorVersion)
+	if err != nil {
+		return nil, err
+	}
+	return generator.Decode(id)
+}
+
+// decodes an id with the transaction's generator and checks that it was generated from its key bytes
+func (c *ClientTransaction) DecodeTransactionID(id string) (*DecodedTransactionID, error) {
+	generator, err := c.generator()
+	if err != nil {
+		return nil, err
+	}
+
+	decoded, err := generator.Decode(id)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(decoded.KeyBytes, c.KeyBytes) {
+		return nil, ErrKeyBytesMismatch
+	}
+	return decoded, nil
+}
+
+// reports whether an id is valid for the given request, as if it was generated by this transaction
+func (c *ClientTransaction) VerifyTransactionID(id, method, path string) (bool, error) {
+	generator, err := c.generator()
+	if err != nil {
+		return false, err
+	}
+
+	decoded, err := c.DecodeTransactionID(id)
+	if err != nil {
+		return false, err
+	}
+	return generator.Verify(c, decoded, method, path), nil
+}
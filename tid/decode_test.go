@@ -0,0 +1,75 @@
+package tid
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDecodeTransactionIDVectors(t *testing.T) {
+	for _, v := range loadVectors(t) {
+		c := v.transaction()
+
+		decoded, err := c.DecodeTransactionID(v.TransactionID)
+		if err != nil {
+			t.Fatalf("%s %s: %v", v.Method, v.Path, err)
+		}
+		if decoded.Version != 1 || decoded.RandomByte != v.RandomByte || decoded.Time.Unix() != v.Time {
+			t.Fatalf("%s %s: decoded %+v", v.Method, v.Path, decoded)
+		}
+
+		if ok, err := c.VerifyTransactionID(v.TransactionID, v.Method, v.Path); err != nil || !ok {
+			t.Fatalf("%s %s: id does not verify: %v", v.Method, v.Path, err)
+		}
+		if ok, _ := c.VerifyTransactionID(v.TransactionID, v.Method, v.Path+"x"); ok {
+			t.Fatalf("%s %s: id verifies for another path", v.Method, v.Path)
+		}
+	}
+}
+
+// a generator that embeds the key bytes and nothing else, to check that transactions dispatch on their version
+type testGenerator struct{}
+
+const testGeneratorVersion = 1000
+
+func init() {
+	RegisterGenerator(testGenerator{})
+}
+
+func (testGenerator) Version() int { return testGeneratorVersion }
+
+func (testGenerator) Derive(c *ClientTransaction, input *DeriveInput) error { return nil }
+
+func (testGenerator) Generate(c *ClientTransaction, method, path string, now time.Time, randomByte byte) (string, error) {
+	return string(c.KeyBytes), nil
+}
+
+func (testGenerator) Decode(id string) (*DecodedTransactionID, error) {
+	return &DecodedTransactionID{Version: testGeneratorVersion, KeyBytes: []byte(id)}, nil
+}
+
+func (testGenerator) Verify(c *ClientTransaction, decoded *DecodedTransactionID, method, path string) bool {
+	return bytes.Equal(decoded.KeyBytes, c.KeyBytes)
+}
+
+func TestTransactionGeneratorDispatch(t *testing.T) {
+	c := &ClientTransaction{Version: testGeneratorVersion, KeyBytes: []byte("key")}
+	id, err := c.GenerateTransactionID("GET", "/")
+	if err != nil || id != "key" {
+		t.Fatalf("got %q (%v), want the test generator's id", id, err)
+	}
+
+	decoded, err := c.DecodeTransactionID(id)
+	if err != nil || decoded.Version != testGeneratorVersion {
+		t.Fatalf("got %+v (%v), want the test generator's decoding", decoded, err)
+	}
+	if ok, err := c.VerifyTransactionID(id, "GET", "/"); err != nil || !ok {
+		t.Fatalf("id does not verify: %v", err)
+	}
+
+	unknown := &ClientTransaction{Version: testGeneratorVersion + 1}
+	if _, err := unknown.GenerateTransactionID("GET", "/"); !errors.Is(err, ErrUnknownGeneratorVersion) {
+		t.Fatalf("got %v, want ErrUnknownGeneratorVersion", err)
+	}
+}
@@ -0,0 +1,42 @@
+package tid
+
+import (
+	"errors"
+	"fmt"
+)
+
+// the steps a ClientTransaction is derived in, reported by StepError
+const (
+	StepHomePage        = "home page"
+	StepChunkMap        = "webpack chunk map"
+	StepOnDemandScript  = "ondemand script"
+	StepKeyByteIndices  = "key byte indices"
+	StepVerificationKey = "verification key"
+	StepAnimationKey    = "animation key"
+)
+
+var (
+	ErrOnDemandChunkNotFound   = errors.New("ondemand.s chunk not found in the webpack chunk map")
+	ErrKeyByteIndicesMissing   = errors.New("key byte indices missing from the ondemand script")
+	ErrKeyByteIndexOutOfRange  = errors.New("key byte index out of range of the verification key")
+	ErrVerificationKeyEmpty    = errors.New("twitter-site-verification key is empty")
+	ErrUnknownGeneratorVersion = errors.New("unknown transaction id generator version")
+)
+
+// StepError tells which step of deriving a ClientTransaction failed
+type StepError struct {
+	Step string
+	Err  error
+}
+
+func (e *StepError) Error() string {
+	return fmt.Sprintf("tid: %s: %v", e.Step, e.Err)
+}
+
+func (e *StepError) Unwrap() error {
+	return e.Err
+}
+
+func stepError(step string, err error) error {
+	return &StepError{Step: step, Err: err}
+}
@@ -0,0 +1,158 @@
+package tid
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+const homePageURL = "https://x.com"
+
+// user agent pages are fetched with unless another one is given, a current desktop Chrome
+const DefaultUserAgent = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/137.0.0.0 Safari/537.36"
+
+var migrationRegex = regexp.MustCompile(`https?://(?:www\.)?(twitter|x)\.com(/x)?/migrate([/?])?tok=[a-zA-Z0-9%\-_]+`)
+
+// Fetcher retrieves the pages a ClientTransaction is derived from
+type Fetcher interface {
+	FetchHomePage(ctx context.Context) (string, error)             // the x.com home page, after any migration redirect
+	FetchOnDemand(ctx context.Context, url string) (string, error) // the ondemand.s script
+}
+
+// HTTPError is returned when a page is answered with a non-2xx status
+type HTTPError struct {
+	URL        string
+	StatusCode int
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("fetching %s: unexpected status %d", e.URL, e.StatusCode)
+}
+
+// HTTPFetcher is the default Fetcher, fetching the live pages with an http client
+type HTTPFetcher struct {
+	Client    *http.Client
+	UserAgent string     // sent with every request, no User-Agent header is set when empty
+	Skew      *ClockSkew // measured from the Date header of the home page responses
+}
+
+// initiates a new http fetcher, a nil client is replaced by http.DefaultClient
+func NewHTTPFetcher(client *http.Client) *HTTPFetcher {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &HTTPFetcher{Client: client, UserAgent: DefaultUserAgent, Skew: NewClockSkew()}
+}
+
+// returns the clock skew measured by the fetcher, transactions derived through it use it by default
+func (f *HTTPFetcher) ClockSkew() *ClockSkew {
+	return f.Skew
+}
+
+func (f *HTTPFetcher) FetchHomePage(ctx context.Context) (string, error) {
+	return f.handleXMigration(ctx)
+}
+
+func (f *HTTPFetcher) FetchOnDemand(ctx context.Context, url string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	return f.do(req, nil)
+}
+
+// fetches the home page, following the meta refresh or the form x.com uses to migrate twitter.com sessions
+func (f *HTTPFetcher) handleXMigration(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, homePageURL, nil)
+	if err != nil {
+		return "", err
+	}
+
+	html, err := f.do(req, f.Skew)
+	if err != nil {
+		return "", err
+	}
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return "", err
+	}
+
+	// Check meta refresh
+	meta := doc.Find(`meta[http-equiv="refresh"]`).First()
+	if content, exists := meta.Attr("content"); exists {
+		if loc := migrationRegex.FindString(content); loc != "" {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, loc, nil)
+			if err != nil {
+				return "", err
+			}
+			return f.do(req, f.Skew)
+		}
+	}
+
+	// Check migration form
+	var form *goquery.Selection
+	form = doc.Find(`form[name="f"]`).First()
+	if form.Length() == 0 {
+		form = doc.Find(`form[action="https://x.com/x/migrate"]`).First()
+	}
+	if form.Length() > 0 {
+		action, _ := form.Attr("action")
+		if action == "" {
+			action = "https://x.com/x/migrate"
+		}
+		method := strings.ToUpper(strings.TrimSpace(getAttr(form, "method", "POST")))
+
+		data := url.Values{}
+		form.Find("input").Each(func(i int, s *goquery.Selection) {
+			name, nameExists := s.Attr("name")
+			value, valueExists := s.Attr("value")
+			if nameExists && valueExists {
+				data.Set(name, value)
+			}
+		})
+
+		var req *http.Request
+		if method == "POST" {
+			req, err = http.NewRequestWithContext(ctx, http.MethodPost, action, strings.NewReader(data.Encode()))
+			if err == nil {
+				req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			}
+		} else {
+			req, err = http.NewRequestWithContext(ctx, http.MethodGet, action+"?"+data.Encode(), nil)
+		}
+		if err != nil {
+			return "", err
+		}
+		return f.do(req, f.Skew)
+	}
+
+	return html, nil
+}
+
+// runs a request and returns its body, non-2xx responses are errors
+func (f *HTTPFetcher) do(req *http.Request, skew *ClockSkew) (string, error) {
+	if f.UserAgent != "" {
+		req.Header.Set("User-Agent", f.UserAgent)
+	}
+
+	resp, err := f.Client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	skew.ObserveResponse(resp)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", &HTTPError{URL: req.URL.String(), StatusCode: resp.StatusCode}
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	return string(body), err
+}
@@ -0,0 +1,86 @@
+package tid
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// answers every request with the page served for its url, a 404 when there is none
+func pagesTransport(pages map[string]string, userAgents *[]string) http.RoundTripper {
+	return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		*userAgents = append(*userAgents, req.Header.Get("User-Agent"))
+
+		status := http.StatusOK
+		page, ok := pages[req.URL.String()]
+		if !ok {
+			status = http.StatusNotFound
+		}
+		return &http.Response{
+			StatusCode: status,
+			Header:     http.Header{},
+			Body:       io.NopCloser(strings.NewReader(page)),
+			Request:    req,
+		}, nil
+	})
+}
+
+func TestHTTPFetcher(t *testing.T) {
+	var userAgents []string
+	onDemandURL := chunksBaseURL + "ondemand.s.abc123a.js"
+	client := &http.Client{Transport: pagesTransport(map[string]string{
+		homePageURL: testHomePage(testKey),
+		onDemandURL: testOnDemandScript(testIndices...),
+	}, &userAgents)}
+
+	c, err := NewClientTransactionWithContext(context.Background(), NewHTTPFetcher(client))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.VerificationKey != testKey {
+		t.Fatalf("got verification key %q", c.VerificationKey)
+	}
+
+	if len(userAgents) != 2 {
+		t.Fatalf("got %d requests, want the home page and the ondemand script", len(userAgents))
+	}
+	for _, userAgent := range userAgents {
+		if userAgent != DefaultUserAgent {
+			t.Fatalf("got user agent %q, want %q", userAgent, DefaultUserAgent)
+		}
+	}
+
+	fetcher := NewHTTPFetcher(client)
+	fetcher.UserAgent = "custom"
+	if _, err := fetcher.FetchOnDemand(context.Background(), onDemandURL); err != nil {
+		t.Fatal(err)
+	}
+	if got := userAgents[len(userAgents)-1]; got != "custom" {
+		t.Fatalf("got user agent %q, want custom", got)
+	}
+}
+
+func TestHTTPFetcherStatusError(t *testing.T) {
+	var userAgents []string
+	client := &http.Client{Transport: pagesTransport(map[string]string{}, &userAgents)}
+
+	_, err := NewClientTransactionWithContext(context.Background(), NewHTTPFetcher(client))
+
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusNotFound || httpErr.URL != homePageURL {
+		t.Fatalf("got %v, want an HTTPError for the home page", err)
+	}
+	var stepErr *StepError
+	if !errors.As(err, &stepErr) || stepErr.Step != StepHomePage {
+		t.Fatalf("got %v, want it to fail at the home page step", err)
+	}
+}
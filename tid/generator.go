@@ -0,0 +1,110 @@
+package tid
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// version used by transactions that do not pick one
+const DefaultGeneratorVersion = 1
+
+/*
+Generator implements one version of the x-client-transaction-id algorithm. When X changes the
+derivation, a new version is added in its own file and registered with RegisterGenerator.
+*/
+type Generator interface {
+	Version() int
+
+	// Derive fills in the algorithm specific state of a transaction: its keyword, additional number and animation key.
+	Derive(c *ClientTransaction, input *DeriveInput) error
+
+	// Generate builds the id of a request sent at the given time, using the given random byte.
+	Generate(c *ClientTransaction, method, path string, now time.Time, randomByte byte) (string, error)
+
+	// Decode splits an id built by Generate into its parts.
+	Decode(id string) (*DecodedTransactionID, error)
+
+	// Verify reports whether a decoded id was built by Generate from the transaction, for the given request.
+	Verify(c *ClientTransaction, decoded *DecodedTransactionID, method, path string) bool
+}
+
+// DeriveInput is everything scraped from x.com that a Generator derives a transaction from
+type DeriveInput struct {
+	KeyBytes       []byte   // decoded twitter-site-verification key
+	FramePaths     []string // path data of each loading animation frame, empty when a frame has none
+	RowIndex       int      // index of the key byte that picks the frame row
+	KeyByteIndices []int    // indices of the key bytes that make up the animation's target time
+}
+
+var (
+	generatorsMu sync.Mutex
+	generators   atomic.Pointer[map[int]Generator] // replaced on every registration, so lookups do not lock
+)
+
+// RegisterGenerator makes a generator available by its version, registering a version twice panics.
+func RegisterGenerator(g Generator) {
+	generatorsMu.Lock()
+	defer generatorsMu.Unlock()
+
+	registered := map[int]Generator{}
+	if current := generators.Load(); current != nil {
+		for version, generator := range *current {
+			registered[version] = generator
+		}
+	}
+
+	if _, exists := registered[g.Version()]; exists {
+		panic(fmt.Sprintf("tid: generator version %d registered twice", g.Version()))
+	}
+	registered[g.Version()] = g
+	generators.Store(&registered)
+}
+
+func registeredGenerators() map[int]Generator {
+	if current := generators.Load(); current != nil {
+		return *current
+	}
+	return nil
+}
+
+// returns the generator registered for a version
+func GetGenerator(version int) (Generator, error) {
+	g, ok := registeredGenerators()[version]
+	if !ok {
+		return nil, fmt.Errorf("tid: %w %d", ErrUnknownGeneratorVersion, version)
+	}
+	return g, nil
+}
+
+// returns every registered version, in ascending order
+func GeneratorVersions() []int {
+	registered := registeredGenerators()
+	versions := make([]int, 0, len(registered))
+	for version := range registered {
+		versions = append(versions, version)
+	}
+	sort.Ints(versions)
+	return versions
+}
+
+// generatorCache is what a generator prepared from a transaction's state, tagged with the generator's version
+type generatorCache struct {
+	version int
+	value   any
+}
+
+// returns what the generator of the given version cached on the transaction, nil when it cached nothing yet
+func (c *ClientTransaction) cached(version int) any {
+	if cache := c.cache.Load(); cache != nil && cache.version == version {
+		return cache.value
+	}
+	return nil
+}
+
+// caches a value prepared by the generator of the given version, replacing whatever was cached before
+func (c *ClientTransaction) storeCache(version int, value any) {
+	c.cache.Store(&generatorCache{version: version, value: value})
+}
@@ -0,0 +1,343 @@
+package tid
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	v1Keyword                = "obfiowerehiring"
+	v1AdditionalRandomNumber = 3
+	v1TotalTime              = 4096.0 // duration of the loading animation, in ms
+
+	transactionEpoch = 1682924400 // unix time the embedded timestamps are relative to
+	hashLength       = 16         // number of sha256 bytes embedded in an id
+
+	// random byte, timestamp, hash and the trailing additional number, everything but the key bytes
+	v1FixedIDLength = 1 + 4 + hashLength + 1
+)
+
+// the key byte that picks the frame
+const v1FrameIndexByte = 5
+
+// a frame row holds two colors, a rotation and at least the 4 points of the bezier curve
+const minFrameRowLength = 3 + 3 + 1 + 4
+
+func init() {
+	RegisterGenerator(generatorV1{})
+}
+
+// generatorV1 is the algorithm X has used since the transaction ids were introduced
+type generatorV1 struct{}
+
+func (generatorV1) Version() int {
+	return 1
+}
+
+func (generatorV1) Derive(c *ClientTransaction, input *DeriveInput) error {
+	trace := c.Trace
+	if trace == nil {
+		trace = &Trace{}
+	}
+	trace.Version = 1
+
+	animationKey, err := getAnimationKey(input, trace)
+	if err != nil {
+		return err
+	}
+
+	c.DefaultKeyword = v1Keyword
+	c.AdditionalRandomNumber = v1AdditionalRandomNumber
+	c.AnimationKey = animationKey
+	return nil
+}
+
+func (generatorV1) Generate(c *ClientTransaction, method, path string, now time.Time, randomByte byte) (string, error) {
+	prepared := prepareV1(c)
+
+	buf := v1Buffers.Get().(*v1Buffer)
+	defer v1Buffers.Put(buf)
+
+	timestamp := uint32(now.Unix()) - transactionEpoch
+
+	hash := v1Hash(buf, method, path, timestamp, prepared.hashSuffix)
+
+	// the random byte, then the key bytes, timestamp, hash and additional number xored with it
+	payload := append(buf.payload[:0], randomByte)
+	for _, b := range prepared.keyBytes {
+		payload = append(payload, b^randomByte)
+	}
+	payload = append(payload,
+		byte(timestamp)^randomByte,
+		byte(timestamp>>8)^randomByte,
+		byte(timestamp>>16)^randomByte,
+		byte(timestamp>>24)^randomByte,
+	)
+	for _, b := range hash[:hashLength] {
+		payload = append(payload, b^randomByte)
+	}
+	payload = append(payload, c.AdditionalRandomNumber^randomByte)
+	buf.payload = payload
+
+	encodedLength := base64.RawStdEncoding.EncodedLen(len(payload))
+	encoded := slices.Grow(buf.encoded[:0], encodedLength)[:encodedLength]
+	base64.RawStdEncoding.Encode(encoded, payload)
+	buf.encoded = encoded
+
+	return string(encoded), nil
+}
+
+func (generatorV1) Decode(id string) (*DecodedTransactionID, error) {
+	raw, err := base64.RawStdEncoding.DecodeString(strings.TrimRight(id, "="))
+	if err != nil {
+		return nil, fmt.Errorf("decoding transaction id: %w", err)
+	}
+	if len(raw) <= v1FixedIDLength {
+		return nil, fmt.Errorf("transaction id too short: %d bytes", len(raw))
+	}
+
+	randomByte := raw[0]
+	data := make([]byte, len(raw)-1)
+	for i, b := range raw[1:] {
+		data[i] = b ^ randomByte
+	}
+
+	keyLength := len(data) - (v1FixedIDLength - 1)
+	timeBytes := data[keyLength : keyLength+4]
+	timestamp := uint32(timeBytes[0]) | uint32(timeBytes[1])<<8 | uint32(timeBytes[2])<<16 | uint32(timeBytes[3])<<24
+
+	return &DecodedTransactionID{
+		Version:                1,
+		RandomByte:             randomByte,
+		KeyBytes:               data[:keyLength],
+		Timestamp:              timestamp,
+		Time:                   time.Unix(int64(timestamp)+transactionEpoch, 0),
+		Hash:                   data[keyLength+4 : keyLength+4+hashLength],
+		AdditionalRandomNumber: data[len(data)-1],
+	}, nil
+}
+
+func (generatorV1) Verify(c *ClientTransaction, decoded *DecodedTransactionID, method, path string) bool {
+	prepared := prepareV1(c)
+
+	buf := v1Buffers.Get().(*v1Buffer)
+	defer v1Buffers.Put(buf)
+
+	hash := v1Hash(buf, method, path, decoded.Timestamp, prepared.hashSuffix)
+	return bytes.Equal(hash[:hashLength], decoded.Hash) && decoded.AdditionalRandomNumber == c.AdditionalRandomNumber
+}
+
+// hashes method!path!timestamp followed by the keyword and the animation key
+func v1Hash(buf *v1Buffer, method, path string, timestamp uint32, hashSuffix []byte) [sha256.Size]byte {
+	hashInput := append(buf.hashInput[:0], method...)
+	hashInput = append(hashInput, '!')
+	hashInput = append(hashInput, path...)
+	hashInput = append(hashInput, '!')
+	hashInput = strconv.AppendUint(hashInput, uint64(timestamp), 10)
+	hashInput = append(hashInput, hashSuffix...)
+	buf.hashInput = hashInput
+
+	return sha256.Sum256(hashInput)
+}
+
+// v1Prepared holds the parts of an id that only change along with the transaction's state
+type v1Prepared struct {
+	keyword      string
+	animationKey string
+	keyBytes     []byte
+	hashSuffix   []byte // keyword followed by the animation key
+}
+
+func (p *v1Prepared) matches(c *ClientTransaction) bool {
+	return p.keyword == c.DefaultKeyword && p.animationKey == c.AnimationKey && bytes.Equal(p.keyBytes, c.KeyBytes)
+}
+
+// returns the prepared parts of the transaction's ids, preparing them again when its state changed
+func prepareV1(c *ClientTransaction) *v1Prepared {
+	if prepared, ok := c.cached(1).(*v1Prepared); ok && prepared.matches(c) {
+		return prepared
+	}
+
+	prepared := &v1Prepared{
+		keyword:      c.DefaultKeyword,
+		animationKey: c.AnimationKey,
+		keyBytes:     bytes.Clone(c.KeyBytes),
+		hashSuffix:   []byte(c.DefaultKeyword + c.AnimationKey),
+	}
+	c.storeCache(1, prepared)
+	return prepared
+}
+
+// scratch space of a single Generate call
+type v1Buffer struct {
+	hashInput []byte
+	payload   []byte
+	encoded   []byte
+}
+
+var v1Buffers = sync.Pool{
+	New: func() any {
+		return new(v1Buffer)
+	},
+}
+
+func getAnimationKey(input *DeriveInput, trace *Trace) (string, error) {
+	keyBytes := input.KeyBytes
+
+	trace.KeyBytes = make([]int, len(keyBytes))
+	for i, b := range keyBytes {
+		trace.KeyBytes[i] = int(b)
+	}
+	trace.RowIndex = input.RowIndex
+	trace.KeyByteIndices = input.KeyByteIndices
+
+	// the input does not always come from NewClientTransactionFromSource, check it again
+	if err := checkKeyByteIndices(keyBytes, input.RowIndex, input.KeyByteIndices); err != nil {
+		return "", err
+	}
+	if len(keyBytes) <= v1FrameIndexByte {
+		return "", fmt.Errorf("%w: index %d, key has %d bytes", ErrKeyByteIndexOutOfRange, v1FrameIndexByte, len(keyBytes))
+	}
+
+	rowIndexValue := int(keyBytes[input.RowIndex] % 16)
+	trace.RowIndexValue = rowIndexValue
+
+	frameTime := 1.0
+	for _, index := range input.KeyByteIndices {
+		frameTime *= float64(keyBytes[index] % 16)
+	}
+
+	frameTime = JsRound(frameTime/10.0) * 10.0
+	targetTime := frameTime / v1TotalTime
+	trace.FrameTime = frameTime
+	trace.TargetTime = targetTime
+
+	frameIndex := int(keyBytes[v1FrameIndexByte] % 4)
+	trace.FrameIndex = frameIndex
+
+	arr, err := get2DArray(input.FramePaths, frameIndex)
+	if err != nil {
+		return "", err
+	}
+	if rowIndexValue >= len(arr) {
+		return "", errors.New("invalid row index")
+	}
+
+	frameRow := arr[rowIndexValue]
+	trace.FrameRow = frameRow
+	if len(frameRow) < minFrameRowLength {
+		return "", fmt.Errorf("frame row has %d values, at least %d are needed", len(frameRow), minFrameRowLength)
+	}
+	animationKey := animate(frameRow, targetTime, trace)
+	trace.AnimationKey = animationKey
+
+	return animationKey, nil
+}
+
+// get2DArray extracts a 2D array of int from the SVG path data of a frame
+func get2DArray(framePaths []string, frameIndex int) ([][]int, error) {
+	if frameIndex >= len(framePaths) {
+		return nil, errors.New("invalid frame index")
+	}
+
+	dAttr := framePaths[frameIndex]
+	if dAttr == "" {
+		return nil, fmt.Errorf("frame %d has no path data", frameIndex)
+	}
+
+	segments, err := ParsePath(dAttr)
+	if err != nil {
+		return nil, fmt.Errorf("parsing frame path: %w", err)
+	}
+
+	// every cubic segment holds one row of the animation
+	var result [][]int
+	for _, segment := range segments {
+		if segment.Command != 'C' {
+			continue
+		}
+
+		row := make([]int, len(segment.Args))
+		for i, arg := range segment.Args {
+			row[i] = int(arg)
+		}
+		result = append(result, row)
+	}
+
+	return result, nil
+}
+
+// solve computes a mapped value with optional rounding
+func solve(value, minVal, maxVal float64, rounding bool) float64 {
+	result := value*(maxVal-minVal)/255.0 + minVal
+	if rounding {
+		return math.Floor(result)
+	}
+	return math.Round(result*100) / 100
+}
+
+// animate generates animation key string from frames and a target time, recording each step in trace
+func animate(frames []int, targetTime float64, trace *Trace) string {
+	fromColor := []float64{float64(frames[0]), float64(frames[1]), float64(frames[2]), 1.0}
+	toColor := []float64{float64(frames[3]), float64(frames[4]), float64(frames[5]), 1.0}
+	fromRotation := []float64{0.0}
+	toRotation := []float64{solve(float64(frames[6]), 60.0, 360.0, true)}
+
+	// Calculate curves applying isOdd for min_val in solve
+	curves := make([]float64, len(frames)-7)
+	for i, val := range frames[7:] {
+		curves[i] = solve(float64(val), float64(IsOdd(int32(i))), 1.0, false)
+	}
+
+	cubic := NewCubic(curves) // You need to implement this spline interpolation struct with GetValue method
+	val := cubic.GetValue(targetTime)
+	trace.Curve = curves
+	trace.CurveValue = val
+
+	color, _ := Interpolate(fromColor, toColor, val) // You need interpolate implementation returning []float64
+	for i := range color {
+		if color[i] < 0 {
+			color[i] = 0
+		} else if color[i] > 255 {
+			color[i] = 255
+		}
+	}
+
+	rotation, _ := Interpolate(fromRotation, toRotation, val)
+	matrix := ConvertRotationToMatrix(rotation[0]) // Implement matrix conversion based on rotation angle
+	trace.Color = color
+	trace.Rotation = rotation[0]
+	trace.Matrix = matrix
+
+	strArr := []string{}
+
+	// Color values as hex (skip alpha)
+	for _, v := range color[:len(color)-1] {
+		strArr = append(strArr, strconv.FormatInt(int64(math.Round(v)), 16))
+	}
+
+	// Matrix values as hex, using floatToHex (implement floatToHex)
+	for _, v := range matrix {
+		rounded := math.Round(v*100) / 100
+		absVal := math.Abs(rounded)
+		strArr = append(strArr, JsFloatToHex(absVal))
+	}
+
+	// Append final zeros
+	strArr = append(strArr, "0", "0")
+	trace.HexPieces = strArr
+
+	animationKey := strings.Join(strArr, "")
+	animationKey = strings.ReplaceAll(animationKey, ".", "")
+	animationKey = strings.ReplaceAll(animationKey, "-", "")
+	return animationKey
+}
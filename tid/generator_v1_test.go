@@ -0,0 +1,108 @@
+package tid
+
+import (
+	"sync"
+	"testing"
+)
+
+const (
+	benchmarkMethod = "POST"
+	benchmarkPath   = "/i/api/graphql/SoVnbfCycZ7fERGCwpZkYA/CreateTweet"
+)
+
+func benchmarkTransaction() *ClientTransaction {
+	return &ClientTransaction{
+		AdditionalRandomNumber: v1AdditionalRandomNumber,
+		DefaultKeyword:         v1Keyword,
+		KeyBytes:               []byte("0123456789abcdef0123456789abcdef0123456789abcdef"),
+		AnimationKey:           "1488a9e1807411208843ccc546c5440e",
+		Skew:                   NewClockSkew(),
+	}
+}
+
+// ids generated concurrently, while the clock skew is updated, must all verify, run it with -race
+func TestGenerateTransactionIDConcurrent(t *testing.T) {
+	c := benchmarkTransaction()
+
+	const goroutines, ids = 16, 500
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < ids; j++ {
+				id, err := c.GenerateTransactionID(benchmarkMethod, benchmarkPath)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if ok, err := c.VerifyTransactionID(id, benchmarkMethod, benchmarkPath); err != nil || !ok {
+					t.Errorf("id %q does not verify: %v", id, err)
+					return
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < ids; i++ {
+		c.Skew.Observe("Mon, 02 Jan 2006 15:04:05 GMT", c.now())
+	}
+	wg.Wait()
+}
+
+// the prepared parts follow the state they were prepared from, whatever was cached meanwhile
+func TestGenerateTransactionIDStateChange(t *testing.T) {
+	c := benchmarkTransaction()
+	generate := func() string {
+		t.Helper()
+
+		id, err := c.GenerateTransactionID(benchmarkMethod, benchmarkPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return id
+	}
+
+	generate()
+	if _, ok := c.cached(1).(*v1Prepared); !ok {
+		t.Fatal("version 1 parts not cached")
+	}
+
+	c.storeCache(testGeneratorVersion, "other")
+	if c.cached(1) != nil {
+		t.Fatal("another version's cache was returned")
+	}
+
+	c.AnimationKey = "0"
+	id := generate()
+	if ok, err := c.VerifyTransactionID(id, benchmarkMethod, benchmarkPath); err != nil || !ok {
+		t.Fatalf("id does not verify against the changed state: %v", err)
+	}
+	if prepared := c.cached(1).(*v1Prepared); prepared.animationKey != "0" {
+		t.Fatalf("got prepared animation key %q, want the changed one", prepared.animationKey)
+	}
+}
+
+func BenchmarkGenerateTransactionID(b *testing.B) {
+	c := benchmarkTransaction()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := c.GenerateTransactionID(benchmarkMethod, benchmarkPath); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGenerateTransactionIDParallel(b *testing.B) {
+	c := benchmarkTransaction()
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := c.GenerateTransactionID(benchmarkMethod, benchmarkPath); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
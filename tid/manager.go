@@ -0,0 +1,157 @@
+package tid
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// how long a refresh triggered in the background may take
+	refreshTimeout = 30 * time.Second
+	// minimum time between two refreshes triggered by rejected ids, so a burst of 404s refreshes once
+	minRejectedRefreshInterval = 30 * time.Second
+)
+
+/*
+TransactionManager owns a ClientTransaction and derives a new one when it gets older than MaxAge,
+or when X rejects an id. The state is swapped atomically, so concurrent callers always see either
+the old or the new transaction, never a mix of both.
+*/
+type TransactionManager struct {
+	MaxAge         time.Duration // age after which the state is refreshed, never when zero
+	OnRefreshError func(error)   // receives the errors of background refreshes
+
+	fetcher Fetcher
+	opts    []Option
+
+	current    atomic.Pointer[ClientTransaction]
+	refreshMu  sync.Mutex
+	refreshing atomic.Bool
+	lastReject atomic.Int64 // unix nano of the last refresh triggered by a rejected id
+}
+
+// initiates a new manager, deriving its first state through the fetcher
+func NewTransactionManager(ctx context.Context, fetcher Fetcher, maxAge time.Duration, opts ...Option) (*TransactionManager, error) {
+	m := &TransactionManager{MaxAge: maxAge, fetcher: fetcher, opts: opts}
+	if err := m.Refresh(ctx); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+/*
+NewTransactionManagerFromState initiates a new manager from a previously stored state, e.g. one unmarshalled from JSON.
+A state without a clock skew uses the one of the fetcher, like the states the manager derives, unless the options set another one.
+*/
+func NewTransactionManagerFromState(state *ClientTransaction, fetcher Fetcher, maxAge time.Duration, opts ...Option) *TransactionManager {
+	if source, ok := fetcher.(interface{ ClockSkew() *ClockSkew }); ok && state.Skew == nil {
+		state.Skew = source.ClockSkew()
+	}
+	for _, opt := range opts {
+		opt(state)
+	}
+
+	m := &TransactionManager{MaxAge: maxAge, fetcher: fetcher, opts: opts}
+	m.current.Store(state)
+	return m
+}
+
+// returns the current state, it must not be modified
+func (m *TransactionManager) Current() *ClientTransaction {
+	return m.current.Load()
+}
+
+// generates an id from the current state, refreshing it in the background when it is stale
+func (m *TransactionManager) GenerateTransactionID(method, path string) (string, error) {
+	current := m.Current()
+	if m.isStale(current) {
+		m.refreshInBackground()
+	}
+	return current.GenerateTransactionID(method, path)
+}
+
+// Refresh derives a new state and swaps it in, concurrent refreshes are serialized.
+func (m *TransactionManager) Refresh(ctx context.Context) error {
+	m.refreshMu.Lock()
+	defer m.refreshMu.Unlock()
+
+	transaction, err := NewClientTransactionWithContext(ctx, m.fetcher, m.opts...)
+	if err != nil {
+		return err
+	}
+
+	m.current.Store(transaction)
+	return nil
+}
+
+/*
+ReportResponse lets the manager inspect the response to a request signed with one of its ids.
+Its Date header feeds the clock skew estimate, and X answers requests carrying an invalid id with a 404, which triggers a background refresh.
+*/
+func (m *TransactionManager) ReportResponse(res *http.Response) {
+	if res == nil {
+		return
+	}
+
+	m.Current().Skew.ObserveResponse(res)
+	if res.StatusCode != http.StatusNotFound {
+		return
+	}
+
+	now := time.Now().UnixNano()
+	last := m.lastReject.Load()
+	if now-last < int64(minRejectedRefreshInterval) || !m.lastReject.CompareAndSwap(last, now) {
+		return
+	}
+	m.refreshInBackground()
+}
+
+// Run refreshes the state whenever it gets stale, until the context is cancelled.
+func (m *TransactionManager) Run(ctx context.Context) error {
+	if m.MaxAge <= 0 {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	ticker := time.NewTicker(m.MaxAge / 4)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if !m.isStale(m.Current()) {
+				continue
+			}
+			if err := m.Refresh(ctx); err != nil && m.OnRefreshError != nil && ctx.Err() == nil {
+				m.OnRefreshError(err)
+			}
+		}
+	}
+}
+
+func (m *TransactionManager) isStale(transaction *ClientTransaction) bool {
+	return m.MaxAge > 0 && transaction.Age() > m.MaxAge
+}
+
+// starts a refresh unless one is already running in the background
+func (m *TransactionManager) refreshInBackground() {
+	if !m.refreshing.CompareAndSwap(false, true) {
+		return
+	}
+
+	go func() {
+		defer m.refreshing.Store(false)
+
+		ctx, cancel := context.WithTimeout(context.Background(), refreshTimeout)
+		defer cancel()
+
+		if err := m.Refresh(ctx); err != nil && m.OnRefreshError != nil {
+			m.OnRefreshError(err)
+		}
+	}()
+}
@@ -0,0 +1,344 @@
+package tid
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var testIndices = []int{2, 12, 14, 7}
+
+// a Fetcher serving the test home page, with the next of its keys on every fetch
+type stubFetcher struct {
+	keys    []string
+	fetches atomic.Int32
+	err     atomic.Pointer[error]
+}
+
+func newStubFetcher(keys ...string) *stubFetcher {
+	return &stubFetcher{keys: keys}
+}
+
+func (f *stubFetcher) FetchHomePage(ctx context.Context) (string, error) {
+	if err := f.err.Load(); err != nil {
+		return "", *err
+	}
+	n := f.fetches.Add(1) - 1
+	return testHomePage(f.keys[int(n)%len(f.keys)]), nil
+}
+
+func (f *stubFetcher) FetchOnDemand(ctx context.Context, url string) (string, error) {
+	if url != chunksBaseURL+"ondemand.s.abc123a.js" {
+		return "", errors.New("unexpected ondemand url " + url)
+	}
+	return testOnDemandScript(testIndices...), nil
+}
+
+// returns another valid verification key, testKey with the bytes read through the key byte indices changed
+func testRotatedKey(t testing.TB) string {
+	t.Helper()
+
+	keyBytes, err := base64.StdEncoding.DecodeString(testKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, index := range testIndices[1:] {
+		keyBytes[index] ^= 0x55
+	}
+	return base64.StdEncoding.EncodeToString(keyBytes)
+}
+
+// a clock that only moves when told to
+type testClock struct {
+	now atomic.Int64
+}
+
+func newTestClock() *testClock {
+	c := &testClock{}
+	c.now.Store(time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC).UnixNano())
+	return c
+}
+
+func (c *testClock) Now() time.Time {
+	return time.Unix(0, c.now.Load())
+}
+
+func (c *testClock) Advance(d time.Duration) {
+	c.now.Add(int64(d))
+}
+
+// waits until cond holds, failing the test after a second
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestTransactionManagerConcurrentSwap(t *testing.T) {
+	keys := []string{testKey, testRotatedKey(t)}
+	fetcher := newStubFetcher(keys...)
+
+	m, err := NewTransactionManager(context.Background(), fetcher, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the states every caller may see, one per key
+	states := map[string]*ClientTransaction{}
+	for _, key := range keys {
+		state, err := NewClientTransactionFromSource(testHomePage(key), testOnDemandScript(testIndices...))
+		if err != nil {
+			t.Fatal(err)
+		}
+		states[key] = state
+	}
+	if states[keys[0]].AnimationKey == states[keys[1]].AnimationKey {
+		t.Fatal("both keys derive the same animation key")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for ctx.Err() == nil {
+			if err := m.Refresh(ctx); err != nil && ctx.Err() == nil {
+				t.Error(err)
+				return
+			}
+		}
+	}()
+
+	var callers sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		callers.Add(1)
+		go func() {
+			defer callers.Done()
+			for j := 0; j < 200; j++ {
+				current := m.Current()
+				state, ok := states[current.VerificationKey]
+				if !ok || current.AnimationKey != state.AnimationKey || !slices.Equal(current.KeyBytes, state.KeyBytes) {
+					t.Errorf("caller saw a mixed state: %+v", current)
+					return
+				}
+
+				id, err := m.GenerateTransactionID("GET", "/i/api/graphql/abc/Test")
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if !verifiesWithAny(id, states) {
+					t.Errorf("id %q was not generated from any of the states", id)
+					return
+				}
+			}
+		}()
+	}
+	callers.Wait()
+	cancel()
+	wg.Wait()
+
+	if fetcher.fetches.Load() < 2 {
+		t.Fatal("the state was never swapped")
+	}
+}
+
+func verifiesWithAny(id string, states map[string]*ClientTransaction) bool {
+	for _, state := range states {
+		if ok, _ := state.VerifyTransactionID(id, "GET", "/i/api/graphql/abc/Test"); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func TestTransactionManagerRefreshWhenStale(t *testing.T) {
+	clock := newTestClock()
+	fetcher := newStubFetcher(testKey, testRotatedKey(t))
+
+	m, err := NewTransactionManager(context.Background(), fetcher, time.Minute, WithClock(clock.Now))
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := m.Current()
+
+	if _, err := m.GenerateTransactionID("GET", "/"); err != nil {
+		t.Fatal(err)
+	}
+	if m.Current() != first || fetcher.fetches.Load() != 1 {
+		t.Fatal("a fresh state was refreshed")
+	}
+
+	clock.Advance(2 * time.Minute)
+	if _, err := m.GenerateTransactionID("GET", "/"); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, func() bool { return m.Current() != first })
+
+	if got := m.Current(); got.VerificationKey == first.VerificationKey || !got.DerivedAt.Equal(clock.Now()) {
+		t.Fatalf("state not refreshed: %+v", got)
+	}
+}
+
+func TestTransactionManagerRefreshError(t *testing.T) {
+	clock := newTestClock()
+	fetcher := newStubFetcher(testKey)
+
+	m, err := NewTransactionManager(context.Background(), fetcher, time.Minute, WithClock(clock.Now))
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := m.Current()
+
+	errs := make(chan error, 1)
+	m.OnRefreshError = func(err error) { errs <- err }
+
+	fetchErr := errors.New("offline")
+	fetcher.err.Store(&fetchErr)
+	clock.Advance(2 * time.Minute)
+
+	// the stale state keeps being used while the refresh fails
+	if _, err := m.GenerateTransactionID("GET", "/"); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-errs:
+		if !errors.Is(err, fetchErr) {
+			t.Fatalf("got %v, want %v", err, fetchErr)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("refresh error not reported")
+	}
+	if m.Current() != first {
+		t.Fatal("state swapped after a failed refresh")
+	}
+}
+
+func TestTransactionManagerReportResponse(t *testing.T) {
+	fetcher := newStubFetcher(testKey)
+	skew := NewClockSkew()
+
+	m, err := NewTransactionManager(context.Background(), fetcher, 0, WithClockSkew(skew))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	response := func(status int) *http.Response {
+		header := http.Header{}
+		header.Set("Date", time.Now().UTC().Format(http.TimeFormat))
+		return &http.Response{StatusCode: status, Header: header}
+	}
+
+	m.ReportResponse(nil)
+	m.ReportResponse(response(http.StatusOK))
+	if fetcher.fetches.Load() != 1 {
+		t.Fatal("a successful response triggered a refresh")
+	}
+	if skew.Samples() != 1 {
+		t.Fatalf("got %d skew samples, want 1", skew.Samples())
+	}
+
+	// a burst of rejected ids refreshes once
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			m.ReportResponse(response(http.StatusNotFound))
+		}()
+	}
+	wg.Wait()
+	waitFor(t, func() bool { return fetcher.fetches.Load() == 2 && !m.refreshing.Load() })
+
+	m.ReportResponse(response(http.StatusNotFound))
+	time.Sleep(10 * time.Millisecond)
+	if got := fetcher.fetches.Load(); got != 2 {
+		t.Fatalf("got %d fetches, want 2", got)
+	}
+
+	// the refreshed state keeps the skew the manager was given
+	if m.Current().Skew != skew || skew.Samples() != 12 {
+		t.Fatalf("skew not carried over, %d samples", skew.Samples())
+	}
+}
+
+func TestClientTransactionJSON(t *testing.T) {
+	clock := newTestClock()
+	c, err := NewClientTransactionFromSource(testHomePage(testKey), testOnDemandScript(testIndices...),
+		WithClock(clock.Now), WithGeneratorVersion(DefaultGeneratorVersion))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var restored ClientTransaction
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Fatal(err)
+	}
+
+	if restored.Version != c.Version || restored.AdditionalRandomNumber != c.AdditionalRandomNumber ||
+		restored.DefaultKeyword != c.DefaultKeyword || !slices.Equal(restored.KeyBytes, c.KeyBytes) ||
+		restored.AnimationKey != c.AnimationKey || !restored.DerivedAt.Equal(c.DerivedAt) ||
+		restored.VerificationKey != testKey {
+		t.Fatalf("got %+v, want %+v", &restored, c)
+	}
+
+	m := NewTransactionManagerFromState(&restored, newStubFetcher(testKey), 0, WithClock(clock.Now))
+	id, err := m.GenerateTransactionID("POST", "/i/api/1.1/test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := c.VerifyTransactionID(id, "POST", "/i/api/1.1/test"); err != nil || !ok {
+		t.Fatalf("id of the restored state does not verify: %v", err)
+	}
+}
+
+// a stubFetcher measuring the clock skew, like HTTPFetcher
+type skewFetcher struct {
+	*stubFetcher
+	skew *ClockSkew
+}
+
+func (f skewFetcher) ClockSkew() *ClockSkew {
+	return f.skew
+}
+
+func TestTransactionManagerFromStateSkew(t *testing.T) {
+	fetcher := skewFetcher{newStubFetcher(testKey), NewClockSkew()}
+
+	m := NewTransactionManagerFromState(&ClientTransaction{}, fetcher, 0)
+	if m.Current().Skew != fetcher.skew {
+		t.Fatal("state without a skew did not get the fetcher's")
+	}
+
+	own := NewClockSkew()
+	m = NewTransactionManagerFromState(&ClientTransaction{Skew: own}, fetcher, 0)
+	if m.Current().Skew != own {
+		t.Fatal("the state's skew was replaced")
+	}
+
+	option := NewClockSkew()
+	m = NewTransactionManagerFromState(&ClientTransaction{}, fetcher, 0, WithClockSkew(option))
+	if m.Current().Skew != option {
+		t.Fatal("the skew set through the options was replaced")
+	}
+}
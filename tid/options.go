@@ -0,0 +1,44 @@
+package tid
+
+import (
+	"io"
+	"time"
+)
+
+// Option configures a ClientTransaction while it is being built
+type Option func(*ClientTransaction)
+
+// sets the clock the timestamp of each id is taken from
+func WithClock(clock func() time.Time) Option {
+	return func(c *ClientTransaction) {
+		c.Clock = clock
+	}
+}
+
+// sets the source the random byte of each id is read from
+func WithRandom(random io.Reader) Option {
+	return func(c *ClientTransaction) {
+		c.Random = random
+	}
+}
+
+// sets the clock skew estimate applied to the timestamp of each id
+func WithClockSkew(skew *ClockSkew) Option {
+	return func(c *ClientTransaction) {
+		c.Skew = skew
+	}
+}
+
+// picks the generator version the transaction is derived and generated with
+func WithGeneratorVersion(version int) Option {
+	return func(c *ClientTransaction) {
+		c.Version = version
+	}
+}
+
+// records the intermediate values of deriving the animation key in trace
+func WithTrace(trace *Trace) Option {
+	return func(c *ClientTransaction) {
+		c.Trace = trace
+	}
+}
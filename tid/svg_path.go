@@ -0,0 +1,137 @@
+package tid
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PathSegment is a single command of SVG path data
+type PathSegment struct {
+	Command  byte      // upper-case command letter, e.g. 'M', 'C', 'L' or 'Z'
+	Relative bool      // whether the command was written in lower case
+	Args     []float64 // every number following the command, implicit repetitions included
+}
+
+// minimum number of arguments each supported command takes
+var pathCommandArity = map[byte]int{
+	'M': 2,
+	'L': 2,
+	'H': 1,
+	'V': 1,
+	'C': 6,
+	'Z': 0,
+}
+
+/*
+ParsePath parses SVG path data into its segments. Implicitly repeated arguments (e.g. "C 1 2 3 4 5 6 7 8 9 10 11 12")
+are kept in the segment of the command they follow, X encodes the loading animation frames that way.
+*/
+func ParsePath(d string) ([]PathSegment, error) {
+	var segments []PathSegment
+
+	pos := 0
+	for {
+		pos = skipPathSeparators(d, pos)
+		if pos >= len(d) {
+			break
+		}
+
+		c := d[pos]
+		upper := c &^ 0x20 // ascii upper case
+		arity, ok := pathCommandArity[upper]
+		if !ok || !isASCIILetter(c) {
+			if len(segments) == 0 {
+				return nil, fmt.Errorf("path data must start with a command, found %q at %d", c, pos)
+			}
+			return nil, fmt.Errorf("unsupported path command %q at %d", c, pos)
+		}
+		pos++
+
+		segment := PathSegment{Command: upper, Relative: c != upper}
+		for {
+			pos = skipPathSeparators(d, pos)
+			if pos >= len(d) || isASCIILetter(d[pos]) {
+				break
+			}
+
+			n, end, err := parsePathNumber(d, pos)
+			if err != nil {
+				return nil, err
+			}
+			segment.Args = append(segment.Args, n)
+			pos = end
+		}
+
+		if arity == 0 && len(segment.Args) > 0 {
+			return nil, fmt.Errorf("command %c takes no arguments", upper)
+		}
+		// repetitions are not checked to be complete, X's frame rows do not follow the bezier arity
+		if len(segment.Args) < arity {
+			return nil, fmt.Errorf("command %c takes %d arguments, got %d", upper, arity, len(segment.Args))
+		}
+
+		segments = append(segments, segment)
+	}
+
+	return segments, nil
+}
+
+// parses a number like 12, -3.5, .5 or 1e-3 starting at pos, returning the offset after it
+func parsePathNumber(d string, pos int) (float64, int, error) {
+	start := pos
+	if pos < len(d) && (d[pos] == '+' || d[pos] == '-') {
+		pos++
+	}
+
+	digits := 0
+	for pos < len(d) && isDigitByte(d[pos]) {
+		pos++
+		digits++
+	}
+	if pos < len(d) && d[pos] == '.' {
+		pos++
+		for pos < len(d) && isDigitByte(d[pos]) {
+			pos++
+			digits++
+		}
+	}
+	if digits == 0 {
+		return 0, 0, fmt.Errorf("invalid number at %d", start)
+	}
+
+	// the exponent, only when it is followed by digits, "e" alone is not a command anyway
+	if pos < len(d) && (d[pos] == 'e' || d[pos] == 'E') {
+		exp := pos + 1
+		if exp < len(d) && (d[exp] == '+' || d[exp] == '-') {
+			exp++
+		}
+		if exp < len(d) && isDigitByte(d[exp]) {
+			for exp < len(d) && isDigitByte(d[exp]) {
+				exp++
+			}
+			pos = exp
+		}
+	}
+
+	n, err := strconv.ParseFloat(d[start:pos], 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid number %q at %d", d[start:pos], start)
+	}
+	return n, pos, nil
+}
+
+func skipPathSeparators(d string, pos int) int {
+	for pos < len(d) && (strings.IndexByte(" \t\n\r\f,", d[pos]) >= 0) {
+		pos++
+	}
+	return pos
+}
+
+func isASCIILetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigitByte(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,108 @@
+package tid
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParsePath(t *testing.T) {
+	tests := []struct {
+		name string
+		d    string
+		want []PathSegment
+	}{
+		{
+			name: "absolute commands",
+			d:    "M 10 30 C 1 2 3 4 5 6 Z",
+			want: []PathSegment{
+				{Command: 'M', Args: []float64{10, 30}},
+				{Command: 'C', Args: []float64{1, 2, 3, 4, 5, 6}},
+				{Command: 'Z'},
+			},
+		},
+		{
+			name: "relative commands",
+			d:    "m1 2l3 4h5v6c1 2 3 4 5 6z",
+			want: []PathSegment{
+				{Command: 'M', Relative: true, Args: []float64{1, 2}},
+				{Command: 'L', Relative: true, Args: []float64{3, 4}},
+				{Command: 'H', Relative: true, Args: []float64{5}},
+				{Command: 'V', Relative: true, Args: []float64{6}},
+				{Command: 'C', Relative: true, Args: []float64{1, 2, 3, 4, 5, 6}},
+				{Command: 'Z', Relative: true},
+			},
+		},
+		{
+			name: "commas and whitespace",
+			d:    "M10,30\tL 1 ,2\n\r,H\f3",
+			want: []PathSegment{
+				{Command: 'M', Args: []float64{10, 30}},
+				{Command: 'L', Args: []float64{1, 2}},
+				{Command: 'H', Args: []float64{3}},
+			},
+		},
+		{
+			name: "exponents",
+			d:    "M 1e2 -2.5E-1 L 3e+1 .5e1",
+			want: []PathSegment{
+				{Command: 'M', Args: []float64{100, -0.25}},
+				{Command: 'L', Args: []float64{30, 5}},
+			},
+		},
+		{
+			name: "numbers without separators",
+			d:    "M 1.5.5 L 12-5",
+			want: []PathSegment{
+				{Command: 'M', Args: []float64{1.5, 0.5}},
+				{Command: 'L', Args: []float64{12, -5}},
+			},
+		},
+		{
+			name: "implicit repetitions",
+			d:    "C 1 2 3 4 5 6 7 8 9 10 11",
+			want: []PathSegment{
+				{Command: 'C', Args: []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}},
+			},
+		},
+		{
+			name: "empty",
+			d:    " , ",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ParsePath(test.d)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestParsePathErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		d    string
+		err  string
+	}{
+		{"unsupported command", "M 0 0 A 1 1 0 0 1 2 2", `unsupported path command 'A' at 6`},
+		{"missing command", "10 20", "path data must start with a command"},
+		{"too few arguments", "M 0 0 C 1 2 3", "command C takes 6 arguments, got 3"},
+		{"arguments after close", "M 0 0 Z 1", "command Z takes no arguments"},
+		{"invalid number", "M 0 - 1", "invalid number at 4"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ParsePath(test.d)
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Fatalf("got %v, want an error containing %q", err, test.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+// generates js_numbers.json, run with: node tid/testdata/js_numbers.js > tid/testdata/js_numbers.json
+const buf = new DataView(new ArrayBuffer(8));
+const bits = (x) => { buf.setFloat64(0, x); return buf.getBigUint64(0).toString(16).padStart(16, "0"); };
+const fromBits = (b) => { buf.setBigUint64(0, b); return buf.getFloat64(0); };
+let seed = 0x2545F4914F6CDD1Dn;
+const next = () => { seed ^= seed << 13n & 0xffffffffffffffffn; seed ^= seed >> 7n; seed ^= seed << 17n & 0xffffffffffffffffn; return seed & 0xffffffffffffffffn; };
+const values = [0, -0, NaN, Infinity, -Infinity, 0.5, -0.5, 1.5, 2.5, -1.5, -2.5, 0.49999999999999994, -0.49999999999999994,
+  0.1, 0.08, 1/3, 2/3, 255, -255.5, 2**53, 2**53 + 2, 1e21, 1e300, Number.MAX_VALUE, Number.MIN_VALUE, Number.EPSILON,
+  4503599627370495.5, 0.9999999999999999, 123.456, -0.0001];
+for (let i = -300; i <= 300; i++) values.push(i / 100);
+for (let i = 0; i < 200; i++) values.push(Math.round(Math.cos(i) * 100) / 100, Math.abs(Math.sin(i)));
+for (let i = 0; i < 300; i++) { const x = fromBits(next()); if (!Number.isNaN(x)) values.push(x); }
+for (let i = 0; i < 200; i++) values.push(Number(next() % 1000000n) / 997 - 500);
+console.log("[\n" + values.map((x) => "  " + JSON.stringify({ bits: bits(x), hex: x.toString(16), round: bits(Math.round(x)) })).join(",\n") + "\n]");
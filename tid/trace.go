@@ -0,0 +1,61 @@
+package tid
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+)
+
+/*
+Trace records the intermediate values of deriving an animation key, so a wrong key can be pinned
+on the step that produced it. Pass one to WithTrace, it is filled in while the transaction is derived.
+Its JSON form can be compared field by field with a trace dumped from the reference implementation.
+*/
+type Trace struct {
+	Version        int       `json:"version"`
+	KeyBytes       []int     `json:"keyBytes"`
+	RowIndex       int       `json:"rowIndex"`      // index of the key byte that picks the frame row
+	RowIndexValue  int       `json:"rowIndexValue"` // the picked frame row
+	KeyByteIndices []int     `json:"keyByteIndices"`
+	FrameTime      float64   `json:"frameTime"`
+	TargetTime     float64   `json:"targetTime"`
+	FrameIndex     int       `json:"frameIndex"`
+	FrameRow       []int     `json:"frameRow"`
+	Curve          []float64 `json:"curve"`      // control points of the bezier curve
+	CurveValue     float64   `json:"curveValue"` // value of the curve at the target time
+	Color          []float64 `json:"color"`
+	Rotation       float64   `json:"rotation"`
+	Matrix         []float64 `json:"matrix"`
+	HexPieces      []string  `json:"hexPieces"`
+	AnimationKey   string    `json:"animationKey"`
+}
+
+/*
+Diff compares the trace with a reference trace in JSON form, e.g. one dumped from the reference
+implementation, and returns the JSON names of the fields that differ, in alphabetical order. Only
+the keys the reference holds are compared, so a partial reference only checks what it holds.
+*/
+func (t *Trace) Diff(reference []byte) ([]string, error) {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return nil, err
+	}
+
+	// both sides are decoded the same way, so numbers compare as float64 whatever produced them
+	var own, other map[string]any
+	if err := json.Unmarshal(data, &own); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(reference, &other); err != nil {
+		return nil, err
+	}
+
+	var fields []string
+	for name, value := range other {
+		if !reflect.DeepEqual(own[name], value) {
+			fields = append(fields, name)
+		}
+	}
+	sort.Strings(fields)
+	return fields, nil
+}
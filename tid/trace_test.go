@@ -0,0 +1,42 @@
+package tid
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestTraceDiff(t *testing.T) {
+	trace := &Trace{}
+	if _, err := NewClientTransactionFromSource(testHomePage(testKey), testOnDemandScript(2, 12, 14, 7), WithTrace(trace)); err != nil {
+		t.Fatal(err)
+	}
+
+	full, err := json.Marshal(trace)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		reference string
+		want      []string
+	}{
+		{"same trace", string(full), nil},
+		{"partial reference", `{"animationKey":"` + trace.AnimationKey + `"}`, nil},
+		{"different fields", `{"animationKey":"abc","frameIndex":9,"frameRow":[1,2]}`, []string{"animationKey", "frameIndex", "frameRow"}},
+		{"unknown field", `{"extra":1}`, []string{"extra"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := trace.Diff([]byte(test.reference))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
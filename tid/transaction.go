@@ -0,0 +1,263 @@
+package tid
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/nitayStain/x-aio/internal/webpack"
+)
+
+const (
+	onDemandChunk = "ondemand.s"
+	chunksBaseURL = "https://abs.twimg.com/responsive-web/client-web/"
+)
+
+var indicesRegex = regexp.MustCompile(`\(\w{1}\[(\d{1,2})\],\s*16\)`)
+
+/*
+ClientTransaction is the state x-client-transaction-id headers are generated from, it can be stored as JSON.
+Ids can be generated from several goroutines at once, as long as the state is not modified meanwhile.
+*/
+type ClientTransaction struct {
+	Version                int       `json:"version"` // generator version, DefaultGeneratorVersion when zero
+	AdditionalRandomNumber byte      `json:"additionalRandomNumber"`
+	DefaultKeyword         string    `json:"defaultKeyword"`
+	KeyBytes               []byte    `json:"keyBytes"`
+	AnimationKey           string    `json:"animationKey"`
+	DerivedAt              time.Time `json:"derivedAt"`       // when the state was derived from the home page
+	VerificationKey        string    `json:"verificationKey"` // twitter-site-verification key the state was derived from
+
+	Clock  func() time.Time `json:"-"` // returns the current time, time.Now when nil
+	Random io.Reader        `json:"-"` // source of the random byte of each id, math/rand/v2 when nil, must be safe for concurrent use
+	Skew   *ClockSkew       `json:"-"` // offset to X's clock, applied to the timestamp of each id
+	Trace  *Trace           `json:"-"` // filled in while the state is derived, when set
+
+	cache atomic.Pointer[generatorCache] // parts of the ids the generator prepared on first use
+}
+
+// initiates a new client transaction, deriving its state from the live x.com home page
+func NewClientTransaction(client *http.Client, opts ...Option) (*ClientTransaction, error) {
+	return NewClientTransactionWithContext(context.Background(), NewHTTPFetcher(client), opts...)
+}
+
+// initiates a new client transaction, fetching the home page and the ondemand script through the fetcher
+func NewClientTransactionWithContext(ctx context.Context, fetcher Fetcher, opts ...Option) (*ClientTransaction, error) {
+	homePage, err := fetcher.FetchHomePage(ctx)
+	if err != nil {
+		return nil, stepError(StepHomePage, err)
+	}
+
+	return NewClientTransactionFromHomePage(ctx, homePage, fetcher, opts...)
+}
+
+/*
+NewClientTransactionFromHomePage initiates a new client transaction from a home page that was already fetched,
+e.g. when it is shared with the operations scraper. Only the ondemand script is fetched through the fetcher.
+*/
+func NewClientTransactionFromHomePage(ctx context.Context, homePage string, fetcher Fetcher, opts ...Option) (*ClientTransaction, error) {
+	onDemandURL, err := OnDemandURL(homePage)
+	if err != nil {
+		return nil, err
+	}
+
+	onDemandScript, err := fetcher.FetchOnDemand(ctx, onDemandURL)
+	if err != nil {
+		return nil, stepError(StepOnDemandScript, err)
+	}
+
+	// use the skew measured while fetching, unless the options set another one
+	if source, ok := fetcher.(interface{ ClockSkew() *ClockSkew }); ok {
+		opts = append([]Option{WithClockSkew(source.ClockSkew())}, opts...)
+	}
+
+	return NewClientTransactionFromSource(homePage, onDemandScript, opts...)
+}
+
+/*
+NewClientTransactionFromSource initiates a new client transaction from a home page and an ondemand
+script that were already fetched, without any request, e.g. from pages stored on disk.
+*/
+func NewClientTransactionFromSource(homePageHTML, onDemandScript string, opts ...Option) (*ClientTransaction, error) {
+	homePage, err := goquery.NewDocumentFromReader(strings.NewReader(homePageHTML))
+	if err != nil {
+		return nil, stepError(StepHomePage, err)
+	}
+
+	rowIndex, keyByteIndices, err := getIndices(onDemandScript)
+	if err != nil {
+		return nil, stepError(StepKeyByteIndices, err)
+	}
+
+	key, err := getKey(homePage)
+	if err != nil {
+		return nil, stepError(StepVerificationKey, err)
+	}
+
+	keyBytes, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return nil, stepError(StepVerificationKey, err)
+	}
+	if len(keyBytes) == 0 {
+		return nil, stepError(StepVerificationKey, ErrVerificationKeyEmpty)
+	}
+
+	if err := checkKeyByteIndices(keyBytes, rowIndex, keyByteIndices); err != nil {
+		return nil, stepError(StepKeyByteIndices, err)
+	}
+
+	c := &ClientTransaction{
+		KeyBytes:        keyBytes,
+		VerificationKey: key,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	generator, err := c.generator()
+	if err != nil {
+		return nil, err
+	}
+
+	err = generator.Derive(c, &DeriveInput{
+		KeyBytes:       keyBytes,
+		FramePaths:     getFramePaths(homePage),
+		RowIndex:       rowIndex,
+		KeyByteIndices: keyByteIndices,
+	})
+	if err != nil {
+		return nil, stepError(StepAnimationKey, err)
+	}
+	c.DerivedAt = c.now()
+
+	return c, nil
+}
+
+// returns the current estimate of how far X's clock is ahead of the local one
+func (c *ClientTransaction) ClockOffset() time.Duration {
+	return c.Skew.Offset()
+}
+
+// returns how long ago the state was derived
+func (c *ClientTransaction) Age() time.Duration {
+	return c.now().Sub(c.DerivedAt)
+}
+
+// returns the url of the ondemand.s script, as built by the webpack runtime inlined in the home page
+func OnDemandURL(homePageHTML string) (string, error) {
+	chunkMap, err := webpack.ParseChunkMap(homePageHTML)
+	if err != nil {
+		return "", stepError(StepChunkMap, err)
+	}
+
+	chunk, ok := chunkMap.Find(onDemandChunk)
+	if !ok {
+		return "", stepError(StepChunkMap, ErrOnDemandChunkNotFound)
+	}
+
+	onDemandURL, err := chunkMap.URL(chunksBaseURL, chunk)
+	if err != nil {
+		return "", stepError(StepChunkMap, err)
+	}
+	return onDemandURL, nil
+}
+
+func getIndices(onDemandScript string) (int, []int, error) {
+	indices := []int{}
+	for _, match := range indicesRegex.FindAllStringSubmatch(onDemandScript, -1) {
+		if len(match) >= 2 {
+			if idx, err := strconv.Atoi(match[1]); err == nil {
+				indices = append(indices, idx)
+			}
+		}
+	}
+
+	if len(indices) < 2 {
+		return 0, nil, ErrKeyByteIndicesMissing
+	}
+
+	return indices[0], indices[1:], nil
+}
+
+// checks that the indices read from the ondemand script point into the verification key
+func checkKeyByteIndices(keyBytes []byte, rowIndex int, keyByteIndices []int) error {
+	for _, index := range append([]int{rowIndex}, keyByteIndices...) {
+		if index < 0 || index >= len(keyBytes) {
+			return fmt.Errorf("%w: index %d, key has %d bytes", ErrKeyByteIndexOutOfRange, index, len(keyBytes))
+		}
+	}
+	return nil
+}
+
+func getKey(doc *goquery.Document) (string, error) {
+	meta := doc.Find(`meta[name="twitter-site-verification"]`).First()
+	content, exists := meta.Attr("content")
+	if !exists {
+		return "", errors.New("twitter-site-verification meta tag not found")
+	}
+	return content, nil
+}
+
+// generates the x-client-transaction-id of a request, with the generator of the transaction's version
+func (c *ClientTransaction) GenerateTransactionID(method, path string) (string, error) {
+	generator, err := c.generator()
+	if err != nil {
+		return "", err
+	}
+
+	randomByte, err := c.randomByte()
+	if err != nil {
+		return "", err
+	}
+
+	return generator.Generate(c, method, path, c.now().Add(c.Skew.Offset()), randomByte)
+}
+
+func (c *ClientTransaction) generator() (Generator, error) {
+	version := c.Version
+	if version == 0 {
+		version = DefaultGeneratorVersion
+	}
+	return GetGenerator(version)
+}
+
+func (c *ClientTransaction) now() time.Time {
+	if c.Clock != nil {
+		return c.Clock()
+	}
+	return time.Now()
+}
+
+func (c *ClientTransaction) randomByte() (byte, error) {
+	// the top level functions of math/rand/v2 draw from a per thread ChaCha8 source, without locking
+	if c.Random == nil {
+		return byte(rand.Uint32()), nil
+	}
+
+	var b [1]byte
+	if _, err := io.ReadFull(c.Random, b[:]); err != nil {
+		return 0, err
+	}
+	return b[0], nil
+}
+
+// getFramePaths returns the path data of every loading animation frame, in document order
+func getFramePaths(doc *goquery.Document) []string {
+	var paths []string
+	doc.Find("[id^='loading-x-anim']").Each(func(i int, frame *goquery.Selection) {
+		// the path is the second child of the frame's first child
+		d, _ := frame.Children().First().Children().Eq(1).Attr("d")
+		paths = append(paths, d)
+	})
+	return paths
+}
@@ -0,0 +1,147 @@
+package tid
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// a transaction id computed by the reference implementation
+type vector struct {
+	Method        string `json:"method"`
+	Path          string `json:"path"`
+	KeyBytes      []byte `json:"keyBytes"`
+	AnimationKey  string `json:"animationKey"`
+	Time          int64  `json:"time"`
+	RandomByte    byte   `json:"randomByte"`
+	TransactionID string `json:"transactionId"`
+}
+
+func loadVectors(t testing.TB) []vector {
+	t.Helper()
+
+	data, err := os.ReadFile("testdata/vectors.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var vectors []vector
+	if err := json.Unmarshal(data, &vectors); err != nil {
+		t.Fatal(err)
+	}
+	return vectors
+}
+
+// builds the transaction a vector was generated with
+func (v vector) transaction() *ClientTransaction {
+	return &ClientTransaction{
+		AdditionalRandomNumber: 3,
+		DefaultKeyword:         "obfiowerehiring",
+		KeyBytes:               v.KeyBytes,
+		AnimationKey:           v.AnimationKey,
+		Clock:                  func() time.Time { return time.Unix(v.Time, 0) },
+		Random:                 bytes.NewReader([]byte{v.RandomByte}),
+	}
+}
+
+func TestGenerateTransactionIDVectors(t *testing.T) {
+	for _, v := range loadVectors(t) {
+		t.Run(v.Method+" "+v.Path, func(t *testing.T) {
+			id, err := v.transaction().GenerateTransactionID(v.Method, v.Path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if id != v.TransactionID {
+				t.Fatalf("got %q, want %q", id, v.TransactionID)
+			}
+		})
+	}
+}
+
+const testFramePath = "M 10,30 C 200 100 50 80 140 30 120 40 10 210 30 C 5 60 90 20 180 70 60 200 90 50 100 h 10 Z"
+
+// builds a home page holding the verification key, the loading animation frames and the webpack runtime
+func testHomePage(key string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, `<html><head><meta name="twitter-site-verification" content="%s"></head><body>`, key)
+	b.WriteString(`<script>o.u=e=>e+"."+{"ondemand.s":"abc123"}[e]+"a.js"</script>`)
+	for i := 0; i < 4; i++ {
+		fmt.Fprintf(&b, `<svg id="loading-x-anim-%d"><g><path d="M 0 0"></path><path d="%s"></path></g></svg>`, i, testFramePath)
+	}
+	b.WriteString("</body></html>")
+	return b.String()
+}
+
+// builds an ondemand script reading the given key byte indices
+func testOnDemandScript(indices ...int) string {
+	var b strings.Builder
+	for _, index := range indices {
+		fmt.Fprintf(&b, "parseInt(n[%d], 16);", index)
+	}
+	return b.String()
+}
+
+const testKey = "Zqgg6jtxHIuDXxl6QDgmcWwgMfgAJzRXLhUQrDqWMR0o63Ty+9xlD/5mZQtEPJzP"
+
+// the animation keys were computed by the reference implementation, from the same page and key byte indices
+func TestNewClientTransactionFromSource(t *testing.T) {
+	tests := []struct {
+		name         string
+		indices      []int
+		animationKey string
+	}{
+		{"start of the animation", []int{2, 12, 14, 7}, "c86432100100"},
+		{"within the animation", []int{2, 14, 7, 14}, "f057390666666666666680eb851eb851eb880eb851eb851eb8806666666666666800"},
+		{"past the end of the animation", []int{2, 14, 7, 14, 7}, "4a5110d70a3d70a3d708087ae147ae147b087ae147ae147b0d70a3d70a3d70800"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c, err := NewClientTransactionFromSource(testHomePage(testKey), testOnDemandScript(test.indices...))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if c.AnimationKey != test.animationKey {
+				t.Fatalf("got animation key %q, want %q", c.AnimationKey, test.animationKey)
+			}
+			if c.DefaultKeyword != "obfiowerehiring" || c.VerificationKey != testKey {
+				t.Fatalf("transaction not derived: %+v", c)
+			}
+		})
+	}
+}
+
+func TestNewClientTransactionFromSourceErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		homePage string
+		script   string
+		step     string
+		err      error
+	}{
+		{"empty key", testHomePage(""), testOnDemandScript(2, 12, 14), StepVerificationKey, ErrVerificationKeyEmpty},
+		{"short key", testHomePage("AAECAwQFBgcI"), testOnDemandScript(80, 1, 2), StepKeyByteIndices, ErrKeyByteIndexOutOfRange},
+		{"index out of range", testHomePage(testKey), testOnDemandScript(2, 12, 80), StepKeyByteIndices, ErrKeyByteIndexOutOfRange},
+		{"key too short for the frame index", testHomePage("AAECAw=="), testOnDemandScript(1, 2, 3), StepAnimationKey, ErrKeyByteIndexOutOfRange},
+		{"indices missing", testHomePage(testKey), testOnDemandScript(2), StepKeyByteIndices, ErrKeyByteIndicesMissing},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewClientTransactionFromSource(test.homePage, test.script)
+
+			var stepErr *StepError
+			if !errors.As(err, &stepErr) {
+				t.Fatalf("got %v, want a StepError", err)
+			}
+			if stepErr.Step != test.step || !errors.Is(err, test.err) {
+				t.Fatalf("got %v, want %v at step %q", err, test.err, test.step)
+			}
+		})
+	}
+}
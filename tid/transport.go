@@ -0,0 +1,80 @@
+package tid
+
+import (
+	"net/http"
+	"strings"
+)
+
+const transactionIDHeader = "x-client-transaction-id"
+
+// hosts signed by a Transport when none are configured
+var defaultTransportHosts = []string{"x.com", "api.x.com"}
+
+/*
+Transport is an http.RoundTripper that signs requests to X with an x-client-transaction-id header.
+Responses are reported to the manager, so a rejected id refreshes the transaction in the background.
+Requests that already carry the header are sent as they are.
+*/
+type Transport struct {
+	Base    http.RoundTripper // transport the requests are sent with, http.DefaultTransport when nil
+	Manager *TransactionManager
+	Hosts   []string // hosts whose requests are signed, x.com and api.x.com when empty
+}
+
+// initiates a new transport that signs requests with the manager's transaction
+func NewTransport(base http.RoundTripper, manager *TransactionManager) *Transport {
+	return &Transport{Base: base, Manager: manager}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !t.signs(req) {
+		return t.base().RoundTrip(req)
+	}
+
+	id, err := t.Manager.GenerateTransactionID(req.Method, req.URL.Path)
+	if err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+
+	// a RoundTripper must not modify the request it was given
+	signed := req.Clone(req.Context())
+	signed.Header.Set(transactionIDHeader, id)
+
+	res, err := t.base().RoundTrip(signed)
+	if err != nil {
+		return nil, err
+	}
+
+	t.Manager.ReportResponse(res)
+	return res, nil
+}
+
+func (t *Transport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+// checks whether a request goes to one of the signed hosts and is not signed yet
+func (t *Transport) signs(req *http.Request) bool {
+	if req.Header.Get(transactionIDHeader) != "" {
+		return false
+	}
+
+	hosts := t.Hosts
+	if len(hosts) == 0 {
+		hosts = defaultTransportHosts
+	}
+
+	hostname := strings.ToLower(req.URL.Hostname())
+	for _, host := range hosts {
+		if hostname == host {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package tid
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestTransport(t *testing.T) {
+	fetcher := newStubFetcher(testKey)
+	m, err := NewTransactionManager(context.Background(), fetcher, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	received := map[string]string{} // transaction id received per host and path
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		received[strings.ToLower(r.Host)+r.URL.Path] = r.Header.Get(transactionIDHeader)
+		mu.Unlock()
+
+		if r.URL.Path == "/rejected" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	// every host is served by the test server
+	base := &http.Transport{
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, network, server.Listener.Addr().String())
+		},
+	}
+	defer base.CloseIdleConnections()
+	client := &http.Client{Transport: NewTransport(base, m)}
+
+	get := func(url string, header string) {
+		t.Helper()
+
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if header != "" {
+			req.Header.Set(transactionIDHeader, header)
+		}
+
+		res, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+
+		if req.Header.Get(transactionIDHeader) != header {
+			t.Fatal("the transport modified the request it was given")
+		}
+	}
+
+	get("http://x.com/i/api/graphql/abc/Test", "")
+	get("http://API.x.com/1.1/test.json", "")
+	get("http://example.com/i/api/graphql/abc/Test", "")
+	get("http://twitter.com/i/api/graphql/abc/Test", "")
+	get("http://x.com/signed", "preset")
+
+	mu.Lock()
+	for _, signed := range []struct{ key, path string }{
+		{"x.com/i/api/graphql/abc/Test", "/i/api/graphql/abc/Test"},
+		{"api.x.com/1.1/test.json", "/1.1/test.json"},
+	} {
+		id := received[signed.key]
+		if ok, err := m.Current().VerifyTransactionID(id, http.MethodGet, signed.path); err != nil || !ok {
+			t.Errorf("%s: got id %q, which does not verify: %v", signed.key, id, err)
+		}
+	}
+	for _, unsigned := range []string{"example.com/i/api/graphql/abc/Test", "twitter.com/i/api/graphql/abc/Test"} {
+		if id := received[unsigned]; id != "" {
+			t.Errorf("%s: got id %q, want none", unsigned, id)
+		}
+	}
+	if id := received["x.com/signed"]; id != "preset" {
+		t.Errorf("got id %q, want the one the request carried", id)
+	}
+	mu.Unlock()
+
+	if fetcher.fetches.Load() != 1 {
+		t.Fatal("a successful response triggered a refresh")
+	}
+
+	get("http://x.com/rejected", "")
+	waitFor(t, func() bool { return fetcher.fetches.Load() == 2 && !m.refreshing.Load() })
+}
@@ -0,0 +1,166 @@
+package tid
+
+import (
+	"encoding/base64"
+	"math"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+/*
+JsRound implements JavaScript's Math.round: halves round towards +Infinity, and values in
+[-0.5, 0) round to -0.
+*/
+func JsRound(num float64) float64 {
+	if math.IsNaN(num) || math.IsInf(num, 0) || num == math.Trunc(num) {
+		return num
+	}
+	if num < 0 && num >= -0.5 {
+		return math.Copysign(0, -1)
+	}
+
+	// floor(num + 0.5) would round 0.49999999999999994 up, since the addition itself rounds
+	rounded := math.Floor(num)
+	if num-rounded >= 0.5 {
+		rounded++
+	}
+	return rounded
+}
+
+// Some kind of weird implementation of a bool (used in twitter's reverse engineered code)
+func IsOdd(num int32) float64 {
+	if num%2 == 1 {
+		return -1.0
+	}
+
+	return 0.0
+}
+
+/*
+JsFloatToHex implements JavaScript's Number.prototype.toString(16). It is a port of V8's
+DoubleToRadixCString, which emits the shortest fraction that still reads back as the same double,
+rounding the last digit half to even.
+*/
+func JsFloatToHex(num float64) string {
+	const radix = 16
+
+	switch {
+	case math.IsNaN(num):
+		return "NaN"
+	case math.IsInf(num, 1):
+		return "Infinity"
+	case math.IsInf(num, -1):
+		return "-Infinity"
+	}
+
+	negative := num < 0
+	if negative {
+		num = -num
+	}
+
+	integer := math.Floor(num)
+	fraction := num - integer
+
+	// half the distance to the next double, digits below it can not change the value read back
+	delta := 0.5 * (math.Nextafter(num, math.Inf(1)) - num)
+	delta = math.Max(math.Nextafter(0, 1), delta)
+
+	var fractionDigits []byte
+	if fraction >= delta {
+		for {
+			fraction *= radix
+			delta *= radix
+			digit := int(fraction)
+			fractionDigits = append(fractionDigits, parseDigit(int64(digit)))
+			fraction -= float64(digit)
+
+			if (fraction > 0.5 || (fraction == 0.5 && digit&1 == 1)) && fraction+delta > 1 {
+				fractionDigits = roundUpDigits(fractionDigits, radix)
+				if fractionDigits == nil {
+					// the carry went past the point
+					integer++
+				}
+				break
+			}
+
+			if fraction < delta {
+				break
+			}
+		}
+	}
+
+	// digits below the double's precision are written as zeros
+	var integerDigits []byte
+	for _, exp := math.Frexp(integer / radix); exp > 53; _, exp = math.Frexp(integer / radix) {
+		integer /= radix
+		integerDigits = append(integerDigits, '0')
+	}
+	for {
+		remainder := math.Mod(integer, radix)
+		integerDigits = append(integerDigits, parseDigit(int64(remainder)))
+		integer = (integer - remainder) / radix
+		if integer <= 0 {
+			break
+		}
+	}
+
+	var result []byte
+	if negative {
+		result = append(result, '-')
+	}
+	for i := len(integerDigits) - 1; i >= 0; i-- {
+		result = append(result, integerDigits[i])
+	}
+	if len(fractionDigits) > 0 {
+		result = append(result, '.')
+		result = append(result, fractionDigits...)
+	}
+	return string(result)
+}
+
+// adds one to the last fraction digit, dropping the digits that carry, returns nil when every digit carried
+func roundUpDigits(digits []byte, radix int) []byte {
+	for len(digits) > 0 {
+		last := len(digits) - 1
+		digit := digitValue(digits[last])
+		if digit+1 < radix {
+			digits[last] = parseDigit(int64(digit + 1))
+			return digits
+		}
+		digits = digits[:last]
+	}
+	return nil
+}
+
+// Encode a byte slice to base64 string
+func base64Encode(data []byte) string {
+	return base64.StdEncoding.EncodeToString(data)
+}
+
+// Decode a base64 string to byte slice
+func base64Decode(input string) ([]byte, error) {
+	return base64.StdEncoding.DecodeString(input)
+}
+
+// helper method to extract a digit, in lowercase like javascript prints them
+func parseDigit(value int64) byte {
+	if value > 9 {
+		return byte('a' + value - 10)
+	}
+	return byte('0' + value)
+}
+
+// reverses parseDigit
+func digitValue(c byte) int {
+	if c > '9' {
+		return int(c-'a') + 10
+	}
+	return int(c - '0')
+}
+
+func getAttr(sel *goquery.Selection, attr, fallback string) string {
+	if val, exists := sel.Attr(attr); exists {
+		return val
+	}
+	return fallback
+}
@@ -0,0 +1,123 @@
+package tid
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// a number along with what V8 returns for it, generated by testdata/js_numbers.js
+type jsNumber struct {
+	Bits  string `json:"bits"`  // IEEE 754 bits of the number, in hex
+	Hex   string `json:"hex"`   // (number).toString(16)
+	Round string `json:"round"` // IEEE 754 bits of Math.round(number), in hex
+}
+
+func loadJSNumbers(t testing.TB) []jsNumber {
+	t.Helper()
+
+	data, err := os.ReadFile("testdata/js_numbers.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var numbers []jsNumber
+	if err := json.Unmarshal(data, &numbers); err != nil {
+		t.Fatal(err)
+	}
+	return numbers
+}
+
+func parseBits(t testing.TB, bits string) float64 {
+	t.Helper()
+
+	n, err := strconv.ParseUint(bits, 16, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return math.Float64frombits(n)
+}
+
+func TestJsFloatToHexV8(t *testing.T) {
+	for _, n := range loadJSNumbers(t) {
+		value := parseBits(t, n.Bits)
+		if got := JsFloatToHex(value); got != n.Hex {
+			t.Errorf("JsFloatToHex(%v) = %q, V8 gives %q", value, got, n.Hex)
+		}
+	}
+}
+
+func TestJsRoundV8(t *testing.T) {
+	for _, n := range loadJSNumbers(t) {
+		value := parseBits(t, n.Bits)
+		want := parseBits(t, n.Round)
+
+		got := JsRound(value)
+		if math.Float64bits(got) != math.Float64bits(want) && !(math.IsNaN(got) && math.IsNaN(want)) {
+			t.Errorf("JsRound(%v) = %v, V8 gives %v", value, got, want)
+		}
+	}
+}
+
+// reads a number printed in base 16, using Go's hex float syntax
+func parseHex(hex string) (float64, error) {
+	sign := ""
+	if strings.HasPrefix(hex, "-") {
+		sign, hex = "-", hex[1:]
+	}
+	return strconv.ParseFloat(sign+"0x"+hex+"p0", 64)
+}
+
+/*
+FuzzJsFloatToHex is seeded with every number of the V8 table and checks those against V8. Other
+inputs are checked against properties V8's output always has: it reads back as the same number,
+only the sign differs between x and -x, and integers below 2^53 print like strconv does.
+*/
+func FuzzJsFloatToHex(f *testing.F) {
+	v8 := map[uint64]string{}
+	for _, n := range loadJSNumbers(f) {
+		bits, err := strconv.ParseUint(n.Bits, 16, 64)
+		if err != nil {
+			f.Fatal(err)
+		}
+		v8[bits] = n.Hex
+		f.Add(bits)
+	}
+
+	f.Fuzz(func(t *testing.T, bits uint64) {
+		value := math.Float64frombits(bits)
+		hex := JsFloatToHex(value)
+
+		if want, ok := v8[bits]; ok && hex != want {
+			t.Fatalf("JsFloatToHex(%v) = %q, V8 gives %q", value, hex, want)
+		}
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return
+		}
+
+		if parsed, err := parseHex(hex); err != nil || parsed != value {
+			t.Fatalf("JsFloatToHex(%v) = %q, which reads back as %v (%v)", value, hex, parsed, err)
+		}
+		if hex != strings.ToLower(hex) {
+			t.Fatalf("JsFloatToHex(%v) = %q, want lowercase digits", value, hex)
+		}
+		if value != 0 && JsFloatToHex(-value) != negate(hex) {
+			t.Fatalf("JsFloatToHex(%v) = %q, but JsFloatToHex(%v) = %q", value, hex, -value, JsFloatToHex(-value))
+		}
+		if value == math.Trunc(value) && math.Abs(value) < 1<<53 {
+			if want := strconv.FormatInt(int64(value), 16); hex != want {
+				t.Fatalf("JsFloatToHex(%v) = %q, want %q", value, hex, want)
+			}
+		}
+	})
+}
+
+func negate(hex string) string {
+	if strings.HasPrefix(hex, "-") {
+		return hex[1:]
+	}
+	return "-" + hex
+}